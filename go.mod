@@ -5,6 +5,7 @@ go 1.20
 require (
 	github.com/gogf/gf/v2 v2.6.1
 	github.com/gvcgo/goutils v0.8.5
+	github.com/oschwald/maxminddb-golang v1.12.0
 	github.com/urfave/cli/v2 v2.25.7
 )
 
@@ -0,0 +1,317 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ngwayzinmoe/uri-to-json/pkgs/outbound"
+	"github.com/ngwayzinmoe/uri-to-json/pkgs/parser"
+	cli "github.com/urfave/cli/v2"
+)
+
+const (
+	FormatJSON      string = "json"
+	FormatJSONLines string = "jsonl"
+)
+
+var nameAsTagFlag = &cli.BoolFlag{
+	Name:  "name-as-tag",
+	Usage: "Use the node name (uri fragment, vmess ps or ssr remarks) as the outbound tag.",
+}
+
+// batchFlags are shared by the commands that support converting many uris at once.
+var batchFlags = []cli.Flag{
+	&cli.StringFlag{
+		Name:    "input",
+		Aliases: []string{"i"},
+		Usage:   "Read uris (one per line) from a file, a directory, or \"-\" for stdin.",
+	},
+	&cli.StringFlag{
+		Name:    "format",
+		Aliases: []string{"f"},
+		Value:   FormatJSON,
+		Usage:   "Batch output format: json (array of outbounds) or jsonl (one outbound per line).",
+	},
+	&cli.StringFlag{
+		Name:  "mmdb",
+		Usage: "Locate nodes with a local MaxMind format GeoIP database, eg. GeoLite2-Country.mmdb.",
+	},
+	&cli.StringSliceFlag{
+		Name:  "country",
+		Usage: "With --mmdb, only keep nodes located in these ISO country codes, eg. --country JP,SG.",
+	},
+	&cli.BoolFlag{
+		Name:  "group-by-country",
+		Usage: "With --mmdb, output an object of outbound arrays keyed by ISO country code.",
+	},
+	nameAsTagFlag,
+	filterFlag,
+}
+
+// UriLine is a single uri together with where it was read from.
+type UriLine struct {
+	Source string
+	Line   int
+	RawUri string
+}
+
+func (that *UriLine) String() string {
+	return fmt.Sprintf("%s:%d", that.Source, that.Line)
+}
+
+// ReadUriLines reads uris from a file, every file in a directory, or stdin when input is "-".
+func ReadUriLines(input string) (lines []*UriLine, err error) {
+	if input == "-" {
+		return scanUriLines("stdin", os.Stdin)
+	}
+	info, err := os.Stat(input)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return readUriFile(input)
+	}
+	err = filepath.WalkDir(input, func(path string, d fs.DirEntry, e error) error {
+		if e != nil || d.IsDir() {
+			return e
+		}
+		l, e := readUriFile(path)
+		lines = append(lines, l...)
+		return e
+	})
+	return
+}
+
+func readUriFile(fPath string) ([]*UriLine, error) {
+	content, err := os.ReadFile(fPath)
+	if err != nil {
+		return nil, err
+	}
+	// a wg-quick config is a single node spanning many lines.
+	if parser.IsWireguardConf(string(content)) {
+		return []*UriLine{{Source: fPath, Line: 1, RawUri: parser.SchemeWireguard + string(content)}}, nil
+	}
+	return scanUriLines(fPath, bytes.NewReader(content))
+}
+
+// ReadRawUri returns arg itself, or a wireguard uri when arg is the path of a wg-quick config.
+func ReadRawUri(arg string) string {
+	if strings.Contains(arg, "://") {
+		return arg
+	}
+	if content, err := os.ReadFile(arg); err == nil && parser.IsWireguardConf(string(content)) {
+		return parser.SchemeWireguard + string(content)
+	}
+	return arg
+}
+
+func scanUriLines(source string, r io.Reader) (lines []*UriLine, err error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	num := 0
+	for scanner.Scan() {
+		num++
+		rawUri := strings.TrimSpace(scanner.Text())
+		if rawUri == "" || strings.HasPrefix(rawUri, "#") {
+			continue
+		}
+		lines = append(lines, &UriLine{Source: source, Line: num, RawUri: rawUri})
+	}
+	return lines, scanner.Err()
+}
+
+// ParseUriLine parses one uri into an outbound for clientType, the same way a single uri is parsed.
+func ParseUriLine(clientType outbound.ClientType, line *UriLine) (outbound.IOutbound, error) {
+	return outbound.ParseOutbound(clientType, line.RawUri)
+}
+
+// ConvertUriLine converts one uri to an outbound string for clientType.
+func ConvertUriLine(clientType outbound.ClientType, line *UriLine) (string, error) {
+	ob, err := ParseUriLine(clientType, line)
+	if err != nil {
+		return "", err
+	}
+	return ob.GetOutboundStr(), nil
+}
+
+// OutboundStr returns the outbound string of ob, tagged by the node name with the --name-as-tag flag.
+func OutboundStr(ctx *cli.Context, clientType outbound.ClientType, ob outbound.IOutbound) string {
+	if !ctx.Bool("name-as-tag") {
+		return ob.GetOutboundStr()
+	}
+	return outbound.SetOutboundTag(clientType, ob.GetOutboundStr(), outbound.ParsedFields(ob).Get("Name").String())
+}
+
+// Converted is a uri converted by ConvertLines.
+type Converted struct {
+	Line       *UriLine
+	Outbound   outbound.IOutbound
+	ClientType outbound.ClientType
+	Location   string // ISO country code, only with --mmdb
+	Tag        string // unique node name, only with --name-as-tag
+}
+
+func (that *Converted) String() string {
+	if that.Tag != "" {
+		return outbound.SetOutboundTag(that.ClientType, that.Outbound.GetOutboundStr(), that.Tag)
+	}
+	return that.Outbound.GetOutboundStr()
+}
+
+// ConvertLines converts every uri from the --input flag.
+// Uris that fail are reported on stderr with their source and line number.
+// With --mmdb every node is located, and --country keeps the nodes of the given countries.
+func ConvertLines(ctx *cli.Context, clientType outbound.ClientType) (result []*Converted, err error) {
+	lines, err := ReadUriLines(ctx.String("input"))
+	if err != nil {
+		return nil, err
+	}
+
+	failed := map[string]int{}
+	for _, line := range lines {
+		ob, err := ParseUriLine(clientType, line)
+		if err != nil {
+			kind := "unknown"
+			if k := parser.ErrorKind(err); k != nil {
+				kind = k.Error()
+			}
+			failed[kind]++
+			fmt.Fprintf(os.Stderr, "%s: %s: %s\n", line, err, line.RawUri)
+			continue
+		}
+		result = append(result, &Converted{Line: line, Outbound: ob, ClientType: clientType})
+	}
+
+	kinds := make([]string, 0, len(failed))
+	total := 0
+	for kind, count := range failed {
+		kinds = append(kinds, kind)
+		total += count
+	}
+	sort.Strings(kinds)
+	for _, kind := range kinds {
+		fmt.Fprintf(os.Stderr, "failed(%s): %d\n", kind, failed[kind])
+	}
+	fmt.Fprintf(os.Stderr, "converted: %d, failed: %d\n", len(result), total)
+
+	if result, err = locateConverted(ctx, result); err != nil {
+		return nil, err
+	}
+	if result, err = filterConverted(ctx, result); err != nil {
+		return nil, err
+	}
+	if ctx.Bool("name-as-tag") {
+		names := make([]string, len(result))
+		for i, c := range result {
+			names[i] = outbound.ParsedFields(c.Outbound).Get("Name").String()
+		}
+		for i, tag := range outbound.UniqueTags(names) {
+			result[i].Tag = tag
+		}
+	}
+	return result, nil
+}
+
+// ConvertBatch converts every uri from the --input flag to outbound strings, see ConvertLines.
+func ConvertBatch(ctx *cli.Context, clientType outbound.ClientType) (outbounds []string, err error) {
+	converted, err := ConvertLines(ctx, clientType)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range converted {
+		outbounds = append(outbounds, c.String())
+	}
+	return outbounds, nil
+}
+
+// ReadResult reads uris from the --input flag, or the args when it is omitted, into a Result.
+// Every node is converted for a capable client, uris that fail are reported on stderr.
+func ReadResult(ctx *cli.Context) (*outbound.Result, error) {
+	var lines []*UriLine
+	if input := ctx.String("input"); input != "" {
+		var err error
+		if lines, err = ReadUriLines(input); err != nil {
+			return nil, err
+		}
+	} else {
+		for i, arg := range ctx.Args().Slice() {
+			lines = append(lines, &UriLine{Source: "args", Line: i + 1, RawUri: ReadRawUri(arg)})
+		}
+	}
+
+	result := outbound.NewResult()
+	failed := 0
+	for _, line := range lines {
+		clientType := outbound.GetCapableClientType(line.RawUri)
+		if _, err := ParseUriLine(clientType, line); err != nil {
+			failed++
+			fmt.Fprintf(os.Stderr, "%s: %s: %s\n", line, err, line.RawUri)
+			continue
+		}
+		result.AddItem(outbound.ParseRawUriToProxyItem(line.RawUri, clientType))
+	}
+	if failed > 0 {
+		fmt.Fprintf(os.Stderr, "parsed: %d, failed: %d\n", result.Len(), failed)
+	}
+	return result, nil
+}
+
+// RunBatch converts every uri from the --input flag and writes them in the --format flag.
+func RunBatch(ctx *cli.Context, clientType outbound.ClientType) error {
+	format := ctx.String("format")
+	if format != FormatJSON && format != FormatJSONLines {
+		return fmt.Errorf("unknown format: %s", format)
+	}
+	if ctx.Bool("group-by-country") && format != FormatJSON {
+		return fmt.Errorf("--group-by-country needs --format %s", FormatJSON)
+	}
+	converted, err := ConvertLines(ctx, clientType)
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(os.Stdout)
+	defer w.Flush()
+	if ctx.Bool("group-by-country") {
+		content, err := json.MarshalIndent(groupByCountry(converted), "", "  ")
+		if err != nil {
+			return err
+		}
+		w.Write(content)
+		w.WriteString("\n")
+		return nil
+	}
+
+	outbounds := make([]string, 0, len(converted))
+	for _, c := range converted {
+		outbounds = append(outbounds, c.String())
+	}
+	if format == FormatJSONLines {
+		for _, o := range outbounds {
+			if content, err := json.Marshal(json.RawMessage(o)); err == nil {
+				w.Write(content)
+				w.WriteString("\n")
+			}
+		}
+		return nil
+	}
+	list := make([]json.RawMessage, 0, len(outbounds))
+	for _, o := range outbounds {
+		list = append(list, json.RawMessage(o))
+	}
+	content, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return err
+	}
+	w.Write(content)
+	w.WriteString("\n")
+	return nil
+}
@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"encoding/base64"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ngwayzinmoe/uri-to-json/pkgs/outbound"
+	"github.com/urfave/cli/v2"
+)
+
+func b64(s string) string {
+	return base64.StdEncoding.EncodeToString([]byte(s))
+}
+
+// batchParityUris must convert the same way one by one and in a batch.
+var batchParityUris = []string{
+	"vless://uuid@example.com:443?type=ws&security=tls&path=%2Fa%2Bb%3Fed%3D2048#n",
+	"vless://uuid@example.com:443?type=ws&security=tls&path=%2Fa%20b#space",
+	"trojan://pa%2Bss@example.com:443?sni=a.com#t%20r",
+	"ss://" + b64("aes-256-gcm:pa/ss+wd") + "@1.2.3.4:8388#s",
+	"ss://" + b64("aes-256-gcm:pass@1.2.3.4:8388") + "#legacy",
+	"ss://YWVzLTI1Ni1nY206Pz8/@1.2.3.4:8388#slash",
+	"ss://2022-blake3-aes-128-gcm:AAAAAAAAAAAAAAAAAAAA%2BA%3D%3D@1.2.3.4:443#k",
+	"ss://2022-blake3-aes-128-gcm:" + "AAAAAAAAAAAAAAAAAAA/%2BA%3D%3D" + "@1.2.3.4:443#slash",
+	"vmess://" + b64(`{"v":"2","ps":"vm","add":"a.com","port":"443","id":"u","net":"ws","path":"/a+b","tls":"tls"}`),
+	"hysteria2://auth@h.example.com:443?sni=a.com;insecure=1#hy2",
+}
+
+func TestBatchParity(t *testing.T) {
+	for _, clientType := range []outbound.ClientType{outbound.XrayCore, outbound.SingBox, outbound.Clash} {
+		for i, rawUri := range batchParityUris {
+			ob, err := outbound.ParseOutbound(clientType, rawUri)
+			if err != nil {
+				t.Errorf("%s: single %s: %s", clientType, rawUri, err)
+				continue
+			}
+			oStr, err := ConvertUriLine(clientType, &UriLine{Source: "test", Line: i + 1, RawUri: rawUri})
+			if err != nil {
+				t.Errorf("%s: batch %s: %s", clientType, rawUri, err)
+				continue
+			}
+			if oStr != ob.GetOutboundStr() {
+				t.Errorf("%s: %s\nsingle: %s\nbatch:  %s", clientType, rawUri, ob.GetOutboundStr(), oStr)
+			}
+		}
+	}
+}
+
+func TestBatchKeepsEscapes(t *testing.T) {
+	oStr, err := ConvertUriLine(outbound.XrayCore, &UriLine{RawUri: batchParityUris[0]})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(oStr, `"path":"/a+b?ed=2048"`) {
+		t.Errorf("ws path was altered: %s", oStr)
+	}
+	oStr, err = ConvertUriLine(outbound.XrayCore, &UriLine{RawUri: batchParityUris[3]})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(oStr, `"password":"pa/ss+wd"`) {
+		t.Errorf("password was altered: %s", oStr)
+	}
+}
+
+func TestReadResultDedup(t *testing.T) {
+	input := filepath.Join(t.TempDir(), "uris.txt")
+	uris := []string{
+		"vless://uuid@example.com:443?type=ws&security=tls&path=%2Fa%2Bb#plus",
+		"vless://uuid@example.com:443?security=tls&type=ws&path=%2Fa%2Bb#plus%20again",
+		"vless://uuid@example.com:443?type=ws&security=tls&path=%2Fa%20b#space",
+	}
+	if err := os.WriteFile(input, []byte(strings.Join(uris, "\n")), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	set := flag.NewFlagSet("dedup", flag.ContinueOnError)
+	set.String("input", input, "")
+	result, err := ReadResult(cli.NewContext(nil, set, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report := result.Dedup(); report.Total() != 1 {
+		t.Errorf("dropped %d nodes, want 1", report.Total())
+	}
+	if result.Len() != 2 || result.Vless[0].RawUri != uris[0] || result.Vless[1].RawUri != uris[2] {
+		for _, item := range result.GetTotalList() {
+			t.Log(item.RawUri)
+		}
+		t.Errorf("kept %d nodes, want %s and %s", result.Len(), uris[0], uris[2])
+	}
+}
@@ -2,10 +2,13 @@ package cmd
 
 import (
 	"fmt"
+	"io"
 	"os"
 
 	"github.com/ngwayzinmoe/uri-to-json/pkgs/outbound"
+	"github.com/ngwayzinmoe/uri-to-json/pkgs/outbound/xray"
 	"github.com/gogf/gf/v2/encoding/gjson"
+	"github.com/gvcgo/goutils/pkgs/gtui"
 	cli "github.com/urfave/cli/v2"
 )
 
@@ -28,7 +31,9 @@ func (that *App) Add(command *cli.Command) {
 }
 
 func (that *App) Run() {
-	that.cmd.Run(os.Args)
+	if err := that.cmd.Run(os.Args); err != nil {
+		gtui.PrintError(err)
+	}
 }
 
 var app *App
@@ -44,18 +49,128 @@ func init() {
 		Name:    "xray",
 		Aliases: []string{"x"},
 		Usage:   "Generate xray-core outbound from vpn url.",
+		Flags:   append(batchFlags, fullConfigFlags...),
+		Action: func(ctx *cli.Context) error {
+			if ctx.Bool("full") {
+				return RunXrayFullConfig(ctx)
+			}
+			if ctx.String("input") != "" {
+				return RunBatch(ctx, outbound.XrayCore)
+			}
+			rawUri := ReadRawUri(ctx.Args().First())
+			if rawUri == "" {
+				return nil
+			}
+			ob, err := outbound.ParseOutbound(outbound.XrayCore, rawUri)
+			if err != nil {
+				return err
+			}
+			fmt.Println(rawUri)
+			ShowOutboundStr(OutboundStr(ctx, outbound.XrayCore, ob))
+			return nil
+		},
+	})
+
+	app.Add(&cli.Command{
+		Name:    "sing",
+		Aliases: []string{"s"},
+		Usage:   "Generate sing-box outbound from vpn url.",
+		Flags:   batchFlags,
 		Action: func(ctx *cli.Context) error {
-			rawUri := ctx.Args().First()
+			if ctx.String("input") != "" {
+				return RunBatch(ctx, outbound.SingBox)
+			}
+			rawUri := ReadRawUri(ctx.Args().First())
 			if rawUri == "" {
 				return nil
 			}
-			ob := outbound.GetOutbound(outbound.XrayCore, rawUri)
-			ob.Parse(rawUri)
+			ob, err := outbound.ParseOutbound(outbound.SingBox, rawUri)
+			if err != nil {
+				return err
+			}
 			fmt.Println(rawUri)
-			ShowOutboundStr(ob.GetOutboundStr())
+			ShowOutboundStr(OutboundStr(ctx, outbound.SingBox, ob))
 			return nil
 		},
 	})
+
+	app.Add(&cli.Command{
+		Name:    "clash",
+		Aliases: []string{"c"},
+		Usage:   "Generate clash/mihomo proxy from vpn url.",
+		Flags:   []cli.Flag{nameAsTagFlag},
+		Action: func(ctx *cli.Context) error {
+			rawUri := ReadRawUri(ctx.Args().First())
+			if rawUri == "" {
+				return nil
+			}
+			ob, err := outbound.ParseOutbound(outbound.Clash, rawUri)
+			if err != nil {
+				return err
+			}
+			fmt.Println(rawUri)
+			fmt.Println(OutboundStr(ctx, outbound.Clash, ob))
+			return nil
+		},
+	})
+
+	app.Add(&cli.Command{
+		Name:      "uri",
+		Aliases:   []string{"u"},
+		Usage:     "Generate vpn url from xray-core outbound json.",
+		ArgsUsage: "[file], reads stdin when file is omitted or \"-\"",
+		Action: func(ctx *cli.Context) error {
+			var (
+				content []byte
+				err     error
+			)
+			if fPath := ctx.Args().First(); fPath != "" && fPath != "-" {
+				content, err = os.ReadFile(fPath)
+			} else {
+				content, err = io.ReadAll(os.Stdin)
+			}
+			if err != nil {
+				return err
+			}
+			uris, err := xray.GetUrisFromConfig(string(content))
+			for _, rawUri := range uris {
+				fmt.Println(rawUri)
+			}
+			return err
+		},
+	})
+
+	app.Add(&cli.Command{
+		Name:      "ping",
+		Usage:     "Measure tcp/tls latency of vpn urls.",
+		ArgsUsage: "[uri...]",
+		Flags:     pingFlags,
+		Action:    RunPing,
+	})
+
+	app.Add(&cli.Command{
+		Name:      "dedup",
+		Usage:     "Drop duplicated vpn urls.",
+		ArgsUsage: "[uri...]",
+		Flags:     dedupFlags,
+		Action:    RunDedup,
+	})
+
+	app.Add(&cli.Command{
+		Name:      "normalize",
+		Aliases:   []string{"n"},
+		Usage:     "Rewrite vpn urls in one canonical form per scheme.",
+		ArgsUsage: "[uri...]",
+		Flags:     normalizeFlags,
+		Action:    RunNormalize,
+	})
+
+	app.Add(&cli.Command{
+		Name:   "serve",
+		Usage:  "Start an http api server for conversions.",
+		Flags:  serveFlags,
+		Action: RunServe,
+	})
 }
 
 func StartApp() {
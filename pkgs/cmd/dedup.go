@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sort"
+
+	cli "github.com/urfave/cli/v2"
+)
+
+var dedupFlags = append([]cli.Flag{
+	&cli.BoolFlag{
+		Name:  "probe",
+		Usage: "Probe every node first and keep the duplicate with the lowest rtt, instead of the first one seen.",
+	},
+}, pingFlags...)
+
+// RunDedup drops duplicated nodes and prints the remaining uris, the dropped ones are counted per scheme on stderr.
+func RunDedup(ctx *cli.Context) error {
+	result, err := ReadResult(ctx)
+	if err != nil {
+		return err
+	}
+	if ctx.Bool("probe") {
+		result.Probe(probeOptions(ctx))
+	}
+	if result, err = filterResult(ctx, result, ctx.Bool("probe")); err != nil {
+		return err
+	}
+	report := result.Dedup()
+
+	schemes := make([]string, 0, len(report))
+	for scheme := range report {
+		schemes = append(schemes, scheme)
+	}
+	sort.Strings(schemes)
+	for _, scheme := range schemes {
+		fmt.Fprintf(os.Stderr, "dropped(%s): %d\n", scheme, report[scheme])
+	}
+	fmt.Fprintf(os.Stderr, "kept: %d, dropped: %d\n", result.Len(), report.Total())
+
+	w := bufio.NewWriter(os.Stdout)
+	defer w.Flush()
+	for _, item := range result.GetTotalList() {
+		w.WriteString(item.RawUri)
+		w.WriteString("\n")
+	}
+	return nil
+}
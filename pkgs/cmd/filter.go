@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/ngwayzinmoe/uri-to-json/pkgs/outbound"
+	cli "github.com/urfave/cli/v2"
+)
+
+var filterFlag = &cli.StringFlag{
+	Name:  "filter",
+	Usage: "Only keep nodes matching an expression, eg. \"scheme=vless && security=reality && port=443 && location!=CN && rtt<300\".",
+}
+
+// readFilter compiles the --filter flag, it is nil when the flag is omitted.
+func readFilter(ctx *cli.Context) (*outbound.Filter, error) {
+	expr := ctx.String("filter")
+	if expr == "" {
+		return nil, nil
+	}
+	return outbound.ParseFilter(expr)
+}
+
+// probeOptions reads the --workers, --timeout and --tls flags, defaults are used for the missing ones.
+func probeOptions(ctx *cli.Context) *outbound.ProbeOptions {
+	opts := outbound.NewProbeOptions()
+	if ctx.IsSet("workers") {
+		opts.Workers = ctx.Int("workers")
+	}
+	if ctx.IsSet("timeout") {
+		opts.Timeout = ctx.Duration("timeout")
+	}
+	opts.TLS = ctx.Bool("tls")
+	return opts
+}
+
+/*
+filterResult applies the --filter flag to result.
+Nodes are located with --mmdb for location conditions, and probed for rtt conditions unless probed is true.
+*/
+func filterResult(ctx *cli.Context, result *outbound.Result, probed bool) (*outbound.Result, error) {
+	f, err := readFilter(ctx)
+	if f == nil {
+		return result, err
+	}
+	if f.Uses("location") {
+		if ctx.String("mmdb") == "" {
+			return nil, fmt.Errorf("location conditions of --filter need --mmdb")
+		}
+		locator, err := outbound.NewLocator(ctx.String("mmdb"))
+		if err != nil {
+			return nil, err
+		}
+		defer locator.Close()
+		result.Locate(locator)
+	}
+	if f.Uses("rtt") && !probed {
+		result.Probe(probeOptions(ctx))
+	}
+	return result.Filter(f), nil
+}
+
+// filterConverted applies the --filter flag to the nodes converted by ConvertLines, locateConverted runs before.
+func filterConverted(ctx *cli.Context, converted []*Converted) ([]*Converted, error) {
+	f, err := readFilter(ctx)
+	if f == nil {
+		return converted, err
+	}
+	if f.Uses("location") && ctx.String("mmdb") == "" {
+		return nil, fmt.Errorf("location conditions of --filter need --mmdb")
+	}
+
+	result := outbound.NewResult()
+	items := make([]*outbound.ProxyItem, len(converted))
+	for i, c := range converted {
+		items[i] = outbound.NewItemByOutbound(c.Outbound, c.ClientType)
+		items[i].Location = c.Location
+		result.AddItem(items[i])
+	}
+	if f.Uses("rtt") {
+		result.Probe(probeOptions(ctx))
+	}
+
+	kept := converted[:0]
+	for i, c := range converted {
+		if f.Match(items[i]) {
+			kept = append(kept, c)
+		}
+	}
+	return kept, nil
+}
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/ngwayzinmoe/uri-to-json/pkgs/outbound"
+	"github.com/ngwayzinmoe/uri-to-json/pkgs/outbound/xray"
+	cli "github.com/urfave/cli/v2"
+)
+
+// fullConfigFlags control the complete xray config generated with --full.
+var fullConfigFlags = []cli.Flag{
+	&cli.BoolFlag{
+		Name:  "full",
+		Usage: "Generate a complete xray config which can be used by \"xray run -c\".",
+	},
+	&cli.StringFlag{
+		Name:  "listen",
+		Value: "127.0.0.1",
+		Usage: "Listen address of the inbounds.",
+	},
+	&cli.IntFlag{
+		Name:  "socks-port",
+		Value: 10808,
+		Usage: "Port of the socks inbound, 0 to disable.",
+	},
+	&cli.IntFlag{
+		Name:  "http-port",
+		Value: 10809,
+		Usage: "Port of the http inbound, 0 to disable.",
+	},
+	&cli.BoolFlag{
+		Name:  "balancer",
+		Usage: "With --full, balance between all outbounds by observatory instead of using the first one.",
+	},
+	&cli.BoolFlag{
+		Name:  "burst",
+		Usage: "With --balancer, use burstObservatory instead of observatory.",
+	},
+	&cli.StringSliceFlag{
+		Name:  "dns",
+		Value: cli.NewStringSlice("1.1.1.1", "8.8.8.8", "localhost"),
+		Usage: "DNS servers.",
+	},
+}
+
+// RunXrayFullConfig wraps the outbound(s) from the args or the --input flag into a full xray config.
+func RunXrayFullConfig(ctx *cli.Context) error {
+	var outbounds []string
+	if ctx.String("input") != "" {
+		var err error
+		if outbounds, err = ConvertBatch(ctx, outbound.XrayCore); err != nil {
+			return err
+		}
+	} else {
+		for _, rawUri := range ctx.Args().Slice() {
+			ob, err := outbound.ParseOutbound(outbound.XrayCore, rawUri)
+			if err != nil {
+				return err
+			}
+			outbounds = append(outbounds, ob.GetOutboundStr())
+		}
+	}
+	if len(outbounds) == 0 {
+		return fmt.Errorf("no outbound to generate config from")
+	}
+
+	opts := xray.NewConfigOptions()
+	opts.Listen = ctx.String("listen")
+	opts.SocksPort = ctx.Int("socks-port")
+	opts.HttpPort = ctx.Int("http-port")
+	opts.DNS = ctx.StringSlice("dns")
+	if ctx.Bool("balancer") {
+		bOpts := xray.NewBalancerOptions()
+		bOpts.Burst = ctx.Bool("burst")
+		fmt.Println(xray.GetBalancerConfig(outbounds, opts, bOpts))
+		return nil
+	}
+	fmt.Println(xray.GetFullConfig(outbounds, opts))
+	return nil
+}
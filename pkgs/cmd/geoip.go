@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/ngwayzinmoe/uri-to-json/pkgs/outbound"
+	cli "github.com/urfave/cli/v2"
+)
+
+// UnknownCountry groups the nodes that could not be located.
+const UnknownCountry string = "unknown"
+
+// locateConverted fills the Location of every node with the --mmdb flag and applies the --country flag.
+func locateConverted(ctx *cli.Context, converted []*Converted) ([]*Converted, error) {
+	countries := map[string]struct{}{}
+	for _, c := range ctx.StringSlice("country") {
+		for _, code := range strings.Split(c, ",") {
+			if code = strings.ToUpper(strings.TrimSpace(code)); code != "" {
+				countries[code] = struct{}{}
+			}
+		}
+	}
+	dbPath := ctx.String("mmdb")
+	if dbPath == "" {
+		if len(countries) > 0 || ctx.Bool("group-by-country") {
+			return nil, fmt.Errorf("--country and --group-by-country need --mmdb")
+		}
+		return converted, nil
+	}
+
+	locator, err := outbound.NewLocator(dbPath)
+	if err != nil {
+		return nil, err
+	}
+	defer locator.Close()
+
+	result := outbound.NewResult()
+	items := make([]*outbound.ProxyItem, len(converted))
+	for i, c := range converted {
+		items[i] = &outbound.ProxyItem{RawUri: c.Outbound.GetRawUri(), Address: c.Outbound.Addr(), Port: c.Outbound.Port()}
+		result.AddItem(items[i])
+	}
+	result.Locate(locator)
+
+	kept := converted[:0]
+	for i, c := range converted {
+		c.Location = items[i].Location
+		if len(countries) > 0 {
+			if _, ok := countries[c.Location]; !ok {
+				continue
+			}
+		}
+		kept = append(kept, c)
+	}
+	return kept, nil
+}
+
+func groupByCountry(converted []*Converted) map[string][]json.RawMessage {
+	groups := map[string][]json.RawMessage{}
+	for _, c := range converted {
+		code := c.Location
+		if code == "" {
+			code = UnknownCountry
+		}
+		groups[code] = append(groups[code], json.RawMessage(c.String()))
+	}
+	return groups
+}
@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+
+	"github.com/ngwayzinmoe/uri-to-json/pkgs/parser"
+	cli "github.com/urfave/cli/v2"
+)
+
+var normalizeFlags = []cli.Flag{
+	&cli.StringFlag{
+		Name:    "input",
+		Aliases: []string{"i"},
+		Usage:   "Read uris (one per line) from a file, a directory, or \"-\" for stdin.",
+	},
+}
+
+// RunNormalize prints the canonical form of every uri from the --input flag or the args, in their order.
+func RunNormalize(ctx *cli.Context) error {
+	var lines []*UriLine
+	if input := ctx.String("input"); input != "" {
+		var err error
+		if lines, err = ReadUriLines(input); err != nil {
+			return err
+		}
+	} else {
+		for i, arg := range ctx.Args().Slice() {
+			lines = append(lines, &UriLine{Source: "args", Line: i + 1, RawUri: ReadRawUri(arg)})
+		}
+	}
+
+	w := bufio.NewWriter(os.Stdout)
+	defer w.Flush()
+	failed := 0
+	for _, line := range lines {
+		rawUri, err := parser.Normalize(line.RawUri)
+		if err != nil {
+			failed++
+			fmt.Fprintf(os.Stderr, "%s: %s: %s\n", line, err, line.RawUri)
+			continue
+		}
+		w.WriteString(rawUri)
+		w.WriteString("\n")
+	}
+	if failed > 0 {
+		fmt.Fprintf(os.Stderr, "normalized: %d, failed: %d\n", len(lines)-failed, failed)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/ngwayzinmoe/uri-to-json/pkgs/outbound"
+	cli "github.com/urfave/cli/v2"
+)
+
+var pingFlags = []cli.Flag{
+	&cli.StringFlag{
+		Name:    "input",
+		Aliases: []string{"i"},
+		Usage:   "Read uris (one per line) from a file, a directory, or \"-\" for stdin.",
+	},
+	&cli.IntFlag{
+		Name:    "workers",
+		Aliases: []string{"w"},
+		Value:   32,
+		Usage:   "Number of nodes probed at the same time.",
+	},
+	&cli.DurationFlag{
+		Name:    "timeout",
+		Aliases: []string{"t"},
+		Value:   outbound.NewProbeOptions().Timeout,
+		Usage:   "Timeout of each node.",
+	},
+	&cli.BoolFlag{
+		Name:  "tls",
+		Usage: "Complete a tls handshake for tls/reality nodes.",
+	},
+	filterFlag,
+	&cli.StringFlag{
+		Name:  "mmdb",
+		Usage: "Locate nodes with a local MaxMind format GeoIP database for the location conditions of --filter.",
+	},
+}
+
+// SortByRTT sorts items by rtt, failed ones (-1) go last.
+func SortByRTT(items []*outbound.ProxyItem) {
+	sort.SliceStable(items, func(i, j int) bool {
+		a, b := items[i].RTT, items[j].RTT
+		if a < 0 || b < 0 {
+			return a >= 0 && b < 0
+		}
+		return a < b
+	})
+}
+
+// RunPing probes every node and prints them sorted by rtt.
+func RunPing(ctx *cli.Context) error {
+	result, err := ReadResult(ctx)
+	if err != nil {
+		return err
+	}
+	result.Probe(probeOptions(ctx))
+	if result, err = filterResult(ctx, result, true); err != nil {
+		return err
+	}
+
+	items := append([]*outbound.ProxyItem{}, result.GetTotalList()...)
+	SortByRTT(items)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "RTT(ms)\tSCHEME\tHOST")
+	for _, item := range items {
+		rtt := "-"
+		if item.RTT >= 0 {
+			rtt = fmt.Sprint(item.RTT)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", rtt, item.Scheme, item.GetHost())
+	}
+	return w.Flush()
+}
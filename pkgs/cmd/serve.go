@@ -0,0 +1,219 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ngwayzinmoe/uri-to-json/pkgs/outbound"
+	"github.com/ngwayzinmoe/uri-to-json/pkgs/parser"
+	cli "github.com/urfave/cli/v2"
+)
+
+/*
+HTTP API of the serve command, every response is json.
+
+POST /convert?client=xray|sing|clash  body: uris, one per line
+	{"outbounds": [...], "errors": [{"line": 2, "uri": "...", "kind": "bad port", "error": "..."}]}
+POST /subscription?client=...        body: subscription payload, plain or base64
+	outbound.Result, client is picked per uri when omitted
+POST /decode                         body: a single uri
+	{"scheme": "vless://", "address": "...", "port": 443, "client": "xray", "fields": {...}}
+
+Failures are reported as {"error": "...", "kind": "..."} with a 4xx status.
+*/
+
+// maxBodySize limits request bodies of the api server.
+const maxBodySize int64 = 16 << 20
+
+var serveFlags = []cli.Flag{
+	&cli.StringFlag{
+		Name:    "listen",
+		Aliases: []string{"l"},
+		Value:   "127.0.0.1:8080",
+		Usage:   "Address the api server listens on.",
+	},
+}
+
+type apiError struct {
+	Error string `json:"error"`
+	Kind  string `json:"kind,omitempty"`
+	Line  int    `json:"line,omitempty"`
+	Uri   string `json:"uri,omitempty"`
+}
+
+func newApiError(err error) *apiError {
+	e := &apiError{Error: err.Error()}
+	if k := parser.ErrorKind(err); k != nil {
+		e.Kind = k.Error()
+	}
+	return e
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, newApiError(err))
+}
+
+// getClientType reads the client query, fallback is returned when it is omitted.
+func getClientType(r *http.Request, fallback outbound.ClientType) (outbound.ClientType, error) {
+	switch r.URL.Query().Get("client") {
+	case "":
+		return fallback, nil
+	case "xray", "x":
+		return outbound.XrayCore, nil
+	case "sing", "s":
+		return outbound.SingBox, nil
+	case "clash", "c":
+		return outbound.Clash, nil
+	default:
+		return "", fmt.Errorf("unknown client: %s", r.URL.Query().Get("client"))
+	}
+}
+
+// readBody returns the request body, only POST is allowed.
+func readBody(w http.ResponseWriter, r *http.Request) ([]byte, bool) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method not allowed: %s", r.Method))
+		return nil, false
+	}
+	content, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodySize))
+	if err != nil {
+		writeError(w, http.StatusRequestEntityTooLarge, err)
+		return nil, false
+	}
+	if len(bytes.TrimSpace(content)) == 0 {
+		writeError(w, http.StatusBadRequest, errors.New("empty body"))
+		return nil, false
+	}
+	return content, true
+}
+
+func handleConvert(w http.ResponseWriter, r *http.Request) {
+	clientType, err := getClientType(r, outbound.XrayCore)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	content, ok := readBody(w, r)
+	if !ok {
+		return
+	}
+
+	var lines []*UriLine
+	if parser.IsWireguardConf(string(content)) {
+		lines = []*UriLine{{Source: "body", Line: 1, RawUri: parser.SchemeWireguard + string(content)}}
+	} else if lines, err = scanUriLines("body", bytes.NewReader(content)); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	outbounds := []interface{}{}
+	failed := []*apiError{}
+	for _, line := range lines {
+		oStr, err := ConvertUriLine(clientType, line)
+		if err != nil {
+			e := newApiError(err)
+			e.Line, e.Uri = line.Line, line.RawUri
+			failed = append(failed, e)
+			continue
+		}
+		if clientType == outbound.Clash {
+			// clash proxies are yaml.
+			outbounds = append(outbounds, oStr)
+		} else {
+			outbounds = append(outbounds, json.RawMessage(oStr))
+		}
+	}
+	if len(outbounds) == 0 && len(failed) > 0 {
+		writeJSON(w, http.StatusUnprocessableEntity, failed[0])
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"outbounds": outbounds,
+		"errors":    failed,
+	})
+}
+
+func handleSubscription(w http.ResponseWriter, r *http.Request) {
+	clientType, err := getClientType(r, "")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	content, ok := readBody(w, r)
+	if !ok {
+		return
+	}
+	var clientTypes []outbound.ClientType
+	if clientType != "" {
+		clientTypes = append(clientTypes, clientType)
+	}
+	result, err := outbound.DecodeSubscription(string(content), clientTypes...)
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+func handleDecode(w http.ResponseWriter, r *http.Request) {
+	content, ok := readBody(w, r)
+	if !ok {
+		return
+	}
+	rawUri := strings.TrimSpace(string(content))
+	if parser.IsWireguardConf(rawUri) {
+		rawUri = parser.SchemeWireguard + rawUri
+	}
+
+	clientType := outbound.GetCapableClientType(rawUri)
+	ob, err := outbound.ParseOutbound(clientType, rawUri)
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+	fields := json.RawMessage(outbound.ParsedFields(ob).MustToJsonString())
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"scheme":  ob.Scheme(),
+		"address": ob.Addr(),
+		"port":    ob.Port(),
+		"client":  clientType,
+		"fields":  fields,
+	})
+}
+
+// NewServeMux returns the handler of the api server.
+func NewServeMux() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/convert", handleConvert)
+	mux.HandleFunc("/subscription", handleSubscription)
+	mux.HandleFunc("/decode", handleDecode)
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, fmt.Errorf("not found: %s", r.URL.Path))
+	})
+	return mux
+}
+
+// RunServe starts the api server on the --listen flag.
+func RunServe(ctx *cli.Context) error {
+	server := &http.Server{
+		Addr:              ctx.String("listen"),
+		Handler:           NewServeMux(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	fmt.Fprintf(os.Stderr, "listening on http://%s\n", server.Addr)
+	return server.ListenAndServe()
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ngwayzinmoe/uri-to-json/pkgs/outbound"
+)
+
+func post(t *testing.T, server *httptest.Server, path, body string) *http.Response {
+	t.Helper()
+	resp, err := http.Post(server.URL+path, "text/plain", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp
+}
+
+// /convert must return the outbound printed by the x and s commands for the same uri.
+func TestServeConvertMatchesCommand(t *testing.T) {
+	server := httptest.NewServer(NewServeMux())
+	defer server.Close()
+
+	for _, clientType := range []outbound.ClientType{outbound.XrayCore, outbound.SingBox} {
+		for _, rawUri := range batchParityUris {
+			ob, err := outbound.ParseOutbound(clientType, rawUri)
+			if err != nil {
+				t.Fatalf("%s: %s", rawUri, err)
+			}
+			resp := post(t, server, "/convert?client="+string(clientType), rawUri)
+			var body struct {
+				Outbounds []json.RawMessage `json:"outbounds"`
+				Errors    []*apiError       `json:"errors"`
+			}
+			err = json.NewDecoder(resp.Body).Decode(&body)
+			resp.Body.Close()
+			if err != nil || resp.StatusCode != http.StatusOK || len(body.Outbounds) != 1 {
+				t.Errorf("%s: %s: status %d, %v, errors %v", clientType, rawUri, resp.StatusCode, err, body.Errors)
+				continue
+			}
+			want := &bytes.Buffer{}
+			if err := json.Compact(want, []byte(ob.GetOutboundStr())); err != nil {
+				t.Fatal(err)
+			}
+			if got := string(body.Outbounds[0]); got != want.String() {
+				t.Errorf("%s: %s\ncommand: %s\nserve:   %s", clientType, rawUri, want, got)
+			}
+		}
+	}
+}
+
+func TestServeDecodeKeepsEscapes(t *testing.T) {
+	server := httptest.NewServer(NewServeMux())
+	defer server.Close()
+
+	resp := post(t, server, "/decode", batchParityUris[0])
+	defer resp.Body.Close()
+	var body struct {
+		Fields map[string]interface{} `json:"fields"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if path := body.Fields["Path"]; path != "/a+b?ed=2048" {
+		t.Errorf("path = %v, want /a+b?ed=2048", path)
+	}
+}
+
+func TestServeErrors(t *testing.T) {
+	server := httptest.NewServer(NewServeMux())
+	defer server.Close()
+
+	for _, c := range []struct {
+		path   string
+		body   string
+		status int
+	}{
+		{"/convert?client=bogus", "trojan://pw@example.com:443", http.StatusBadRequest},
+		{"/convert", "vless://uuid@example.com:0", http.StatusUnprocessableEntity},
+		{"/decode", "  ", http.StatusBadRequest},
+		{"/nothing", "x", http.StatusNotFound},
+	} {
+		resp := post(t, server, c.path, c.body)
+		resp.Body.Close()
+		if resp.StatusCode != c.status {
+			t.Errorf("%s: status %d, want %d", c.path, resp.StatusCode, c.status)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package clash
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ngwayzinmoe/uri-to-json/pkgs/parser"
+	"github.com/ngwayzinmoe/uri-to-json/pkgs/utils"
+	"github.com/gogf/gf/v2/encoding/gjson"
+	"github.com/gogf/gf/v2/util/gconv"
+)
+
+/*
+https://wiki.metacubex.one/config/proxies/hysteria/
+
+- name: "hysteria"
+  type: hysteria
+  server: server.com
+  port: 443
+  auth-str: yourpassword
+  obfs: obfs_str
+  alpn:
+    - h3
+  protocol: udp
+  up: "30 Mbps"
+  down: "200 Mbps"
+  sni: server.com
+  skip-cert-verify: false
+*/
+
+var ClashHysteria string = `{
+	"name": "hysteria",
+	"type": "hysteria",
+	"server": "127.0.0.1",
+	"port": 443,
+	"protocol": "udp",
+	"up": "100 Mbps",
+	"down": "100 Mbps"
+}`
+
+type CHysteriaOut struct {
+	RawUri   string
+	Parser   *parser.ParserHysteria
+	outbound string
+}
+
+func (that *CHysteriaOut) Parse(rawUri string) error {
+	that.Parser = &parser.ParserHysteria{}
+	return that.Parser.Parse(rawUri)
+}
+
+func (that *CHysteriaOut) Addr() string {
+	if that.Parser == nil {
+		return ""
+	}
+	return that.Parser.GetAddr()
+}
+
+func (that *CHysteriaOut) Port() int {
+	if that.Parser == nil {
+		return 0
+	}
+	return that.Parser.GetPort()
+}
+
+func (that *CHysteriaOut) Scheme() string {
+	return parser.SchemeHysteria
+}
+
+func (that *CHysteriaOut) GetRawUri() string {
+	return that.RawUri
+}
+
+func (that *CHysteriaOut) getSettings() *gjson.Json {
+	j := gjson.New(ClashHysteria)
+	j.Set("name", utils.OutboundTag)
+	j.Set("server", that.Parser.Address)
+	j.Set("port", that.Parser.Port)
+	j.Set("protocol", that.Parser.Protocol)
+	if that.Parser.UpMbps > 0 {
+		j.Set("up", fmt.Sprintf("%d Mbps", that.Parser.UpMbps))
+	}
+	if that.Parser.DownMbps > 0 {
+		j.Set("down", fmt.Sprintf("%d Mbps", that.Parser.DownMbps))
+	}
+	if that.Parser.Auth != "" {
+		j.Set("auth-str", that.Parser.Auth)
+	}
+	if that.Parser.OBFSParam != "" {
+		j.Set("obfs", that.Parser.OBFSParam)
+	}
+	if that.Parser.TLSALPN != "" {
+		j.Set("alpn", strings.Split(that.Parser.TLSALPN, ","))
+	}
+	if that.Parser.ServerName != "" {
+		j.Set("sni", that.Parser.ServerName)
+	}
+	j.Set("skip-cert-verify", gconv.Bool(that.Parser.TLSAllowInsecure))
+	return j
+}
+
+func (that *CHysteriaOut) GetOutboundStr() string {
+	if that.Parser.Address == "" && that.Parser.Port == 0 {
+		return ""
+	}
+	if that.outbound == "" {
+		that.outbound = ToProxiesYaml(that.getSettings())
+	}
+	return that.outbound
+}
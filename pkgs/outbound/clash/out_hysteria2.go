@@ -0,0 +1,107 @@
+package clash
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ngwayzinmoe/uri-to-json/pkgs/parser"
+	"github.com/ngwayzinmoe/uri-to-json/pkgs/utils"
+	"github.com/gogf/gf/v2/encoding/gjson"
+)
+
+/*
+https://wiki.metacubex.one/config/proxies/hysteria2/
+
+- name: "hysteria2"
+  type: hysteria2
+  server: server.com
+  port: 443
+  password: yourpassword
+  up: "30 Mbps"
+  down: "200 Mbps"
+  obfs: salamander
+  obfs-password: yourpassword
+  sni: server.com
+  skip-cert-verify: false
+*/
+
+var ClashHysteria2 string = `{
+	"name": "hysteria2",
+	"type": "hysteria2",
+	"server": "127.0.0.1",
+	"port": 443,
+	"password": ""
+}`
+
+type CHysteria2Out struct {
+	RawUri   string
+	Parser   *parser.ParserHysteria2
+	outbound string
+}
+
+func (that *CHysteria2Out) Parse(rawUri string) error {
+	that.Parser = &parser.ParserHysteria2{}
+	return that.Parser.Parse(rawUri)
+}
+
+func (that *CHysteria2Out) Addr() string {
+	if that.Parser == nil {
+		return ""
+	}
+	return that.Parser.GetAddr()
+}
+
+func (that *CHysteria2Out) Port() int {
+	if that.Parser == nil {
+		return 0
+	}
+	return that.Parser.GetPort()
+}
+
+func (that *CHysteria2Out) Scheme() string {
+	return parser.SchemeHysteria2
+}
+
+func (that *CHysteria2Out) GetRawUri() string {
+	return that.RawUri
+}
+
+func (that *CHysteria2Out) getSettings() *gjson.Json {
+	j := gjson.New(ClashHysteria2)
+	j.Set("name", utils.OutboundTag)
+	j.Set("server", that.Parser.Config.Server)
+	j.Set("port", that.Parser.Config.Port)
+	j.Set("password", that.Parser.Config.Auth)
+	if that.Parser.Config.OBFSPass != "" {
+		obfs := that.Parser.Config.OBFS
+		if obfs == "" {
+			obfs = "salamander"
+		}
+		j.Set("obfs", obfs)
+		j.Set("obfs-password", that.Parser.Config.OBFSPass)
+	}
+	if that.Parser.Config.SNI != "" {
+		j.Set("sni", that.Parser.Config.SNI)
+	}
+	j.Set("skip-cert-verify", that.Parser.Config.Insecure)
+	if that.Parser.Config.PinSHA256 != "" {
+		j.Set("fingerprint", strings.ToLower(strings.ReplaceAll(that.Parser.Config.PinSHA256, ":", "")))
+	}
+	if that.Parser.Config.UpMbps > 0 {
+		j.Set("up", fmt.Sprintf("%d Mbps", that.Parser.Config.UpMbps))
+	}
+	if that.Parser.Config.DownMbps > 0 {
+		j.Set("down", fmt.Sprintf("%d Mbps", that.Parser.Config.DownMbps))
+	}
+	return j
+}
+
+func (that *CHysteria2Out) GetOutboundStr() string {
+	if that.Parser.Config.Server == "" || that.Parser.Config.Port == 0 {
+		return ""
+	}
+	if that.outbound == "" {
+		that.outbound = ToProxiesYaml(that.getSettings())
+	}
+	return that.outbound
+}
@@ -0,0 +1,31 @@
+package clash
+
+import (
+	"github.com/gogf/gf/v2/encoding/gjson"
+)
+
+/*
+https://wiki.metacubex.one/config/proxies/
+
+proxies:
+  - name: "proxy"
+    type: vmess
+    server: server
+    port: 443
+    udp: true
+    ...
+*/
+
+var ClashProxies string = `{
+	"proxies": []
+}`
+
+// ToProxiesYaml wraps a single Clash proxy object into a "proxies:" YAML entry.
+func ToProxiesYaml(proxy *gjson.Json) string {
+	if proxy == nil {
+		return ""
+	}
+	j := gjson.New(ClashProxies)
+	j.Set("proxies.0", proxy.Map())
+	return j.MustToYamlString()
+}
@@ -0,0 +1,123 @@
+package clash
+
+import (
+	"fmt"
+
+	"github.com/ngwayzinmoe/uri-to-json/pkgs/parser"
+	"github.com/ngwayzinmoe/uri-to-json/pkgs/utils"
+	"github.com/gogf/gf/v2/encoding/gjson"
+)
+
+/*
+https://wiki.metacubex.one/config/proxies/ss/
+
+- name: "ss1"
+  type: ss
+  server: server
+  port: 443
+  cipher: aes-128-gcm
+  password: "password"
+  udp: true
+  udp-over-tcp: false
+  plugin: obfs
+  plugin-opts:
+    mode: tls
+    host: bing.com
+*/
+
+var ClashSS string = `{
+	"name": "ss",
+	"type": "ss",
+	"server": "127.0.0.1",
+	"port": 443,
+	"cipher": "aes-128-gcm",
+	"password": "",
+	"udp": true
+}`
+
+type CShadowSocksOut struct {
+	RawUri   string
+	Parser   *parser.ParserSS
+	outbound string
+}
+
+func (that *CShadowSocksOut) Parse(rawUri string) error {
+	that.Parser = &parser.ParserSS{}
+	if err := that.Parser.Parse(rawUri); err != nil {
+		return err
+	}
+	switch {
+	case that.Parser.Plugin == "", that.Parser.Plugin == parser.SSPluginObfs:
+		return nil
+	case that.Parser.Plugin == parser.SSPluginV2ray && that.Parser.Mode == "websocket":
+		return nil
+	default:
+		return parser.NewParseError(parser.ErrUnsupportedTransport, parser.SchemeSS, "plugin", that.Parser.Plugin)
+	}
+}
+
+func (that *CShadowSocksOut) Addr() string {
+	if that.Parser == nil {
+		return ""
+	}
+	return that.Parser.GetAddr()
+}
+
+func (that *CShadowSocksOut) Port() int {
+	if that.Parser == nil {
+		return 0
+	}
+	return that.Parser.GetPort()
+}
+
+func (that *CShadowSocksOut) Scheme() string {
+	return parser.SchemeSS
+}
+
+func (that *CShadowSocksOut) GetRawUri() string {
+	return that.RawUri
+}
+
+func (that *CShadowSocksOut) getSettings() *gjson.Json {
+	j := gjson.New(ClashSS)
+	j.Set("name", utils.OutboundTag)
+	j.Set("server", that.Parser.Address)
+	j.Set("port", that.Parser.Port)
+	j.Set("cipher", that.Parser.Method)
+	j.Set("password", that.Parser.Password)
+	switch that.Parser.Plugin {
+	case parser.SSPluginObfs:
+		j.Set("plugin", "obfs")
+		j.Set("plugin-opts.mode", that.Parser.OBFS)
+		if that.Parser.OBFSHost != "" {
+			j.Set("plugin-opts.host", that.Parser.OBFSHost)
+		}
+	case parser.SSPluginV2ray:
+		j.Set("plugin", "v2ray-plugin")
+		j.Set("plugin-opts.mode", that.Parser.Mode)
+		j.Set("plugin-opts.tls", that.Parser.StreamSecurity == "tls")
+		if that.Parser.Host != "" {
+			j.Set("plugin-opts.host", that.Parser.Host)
+		}
+		j.Set("plugin-opts.path", that.Parser.Path)
+		j.Set("plugin-opts.mux", that.Parser.PluginMux() > 0)
+	}
+	return j
+}
+
+func (that *CShadowSocksOut) GetOutboundStr() string {
+	if that.Parser.Address == "" && that.Parser.Port == 0 {
+		return ""
+	}
+	if that.outbound == "" {
+		that.outbound = ToProxiesYaml(that.getSettings())
+	}
+	return that.outbound
+}
+
+func TestSS() {
+	rawUri := "ss://aes-256-gcm:bad5fba5-a7bc-4709-882b-e15edad16cef@ah-cmi-1000m.ikun666.club:18878#🇨🇳_CN_中国->🇸🇬_SG_新加坡"
+	sso := &CShadowSocksOut{}
+	sso.Parse(rawUri)
+	fmt.Println(sso.GetOutboundStr())
+}
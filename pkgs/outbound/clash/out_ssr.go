@@ -0,0 +1,98 @@
+package clash
+
+import (
+	"github.com/ngwayzinmoe/uri-to-json/pkgs/parser"
+	"github.com/ngwayzinmoe/uri-to-json/pkgs/utils"
+	"github.com/gogf/gf/v2/encoding/gjson"
+)
+
+/*
+https://wiki.metacubex.one/config/proxies/ssr/
+
+- name: "ssr"
+  type: ssr
+  server: server
+  port: 443
+  cipher: chacha20-ietf
+  password: "password"
+  obfs: tls1.2_ticket_auth
+  protocol: auth_sha1_v4
+  obfs-param: domain.tld
+  protocol-param: "#"
+  udp: true
+*/
+
+var ClashSSR string = `{
+	"name": "ssr",
+	"type": "ssr",
+	"server": "127.0.0.1",
+	"port": 443,
+	"cipher": "aes-128-cfb",
+	"password": "",
+	"obfs": "plain",
+	"protocol": "origin",
+	"udp": true
+}`
+
+type CShadowSocksROut struct {
+	RawUri   string
+	Parser   *parser.ParserSSR
+	outbound string
+}
+
+func (that *CShadowSocksROut) Parse(rawUri string) error {
+	that.Parser = &parser.ParserSSR{}
+	return that.Parser.Parse(rawUri)
+}
+
+func (that *CShadowSocksROut) Addr() string {
+	if that.Parser == nil {
+		return ""
+	}
+	return that.Parser.GetAddr()
+}
+
+func (that *CShadowSocksROut) Port() int {
+	if that.Parser == nil {
+		return 0
+	}
+	return that.Parser.GetPort()
+}
+
+func (that *CShadowSocksROut) Scheme() string {
+	return parser.SchemeSSR
+}
+
+func (that *CShadowSocksROut) GetRawUri() string {
+	return that.RawUri
+}
+
+func (that *CShadowSocksROut) getSettings() *gjson.Json {
+	j := gjson.New(ClashSSR)
+	j.Set("name", utils.OutboundTag)
+	j.Set("server", that.Parser.Address)
+	j.Set("port", that.Parser.Port)
+	j.Set("cipher", that.Parser.Method)
+	j.Set("password", that.Parser.Password)
+	j.Set("obfs", that.Parser.OBFS)
+	if that.Parser.Proto != "" {
+		j.Set("protocol", that.Parser.Proto)
+	}
+	if that.Parser.OBFSParam != "" {
+		j.Set("obfs-param", that.Parser.OBFSParam)
+	}
+	if that.Parser.ProtoParam != "" {
+		j.Set("protocol-param", that.Parser.ProtoParam)
+	}
+	return j
+}
+
+func (that *CShadowSocksROut) GetOutboundStr() string {
+	if that.Parser.Address == "" && that.Parser.Port == 0 {
+		return ""
+	}
+	if that.outbound == "" {
+		that.outbound = ToProxiesYaml(that.getSettings())
+	}
+	return that.outbound
+}
@@ -0,0 +1,103 @@
+package clash
+
+import (
+	"fmt"
+
+	"github.com/ngwayzinmoe/uri-to-json/pkgs/parser"
+	"github.com/ngwayzinmoe/uri-to-json/pkgs/utils"
+	"github.com/gogf/gf/v2/encoding/gjson"
+)
+
+/*
+https://wiki.metacubex.one/config/proxies/trojan/
+
+- name: "trojan"
+  type: trojan
+  server: server
+  port: 443
+  password: yourpsk
+  udp: true
+  sni: example.com
+  alpn:
+    - h2
+    - http/1.1
+  skip-cert-verify: true
+*/
+
+var ClashTrojan string = `{
+	"name": "trojan",
+	"type": "trojan",
+	"server": "127.0.0.1",
+	"port": 443,
+	"udp": true,
+	"password": ""
+}`
+
+type CTrojanOut struct {
+	RawUri   string
+	Parser   *parser.ParserTrojan
+	outbound string
+}
+
+func (that *CTrojanOut) Parse(rawUri string) error {
+	that.Parser = &parser.ParserTrojan{}
+	if err := that.Parser.Parse(rawUri); err != nil {
+		return err
+	}
+	return CheckNetwork(parser.SchemeTrojan, that.Parser.StreamField)
+}
+
+func (that *CTrojanOut) Addr() string {
+	if that.Parser == nil {
+		return ""
+	}
+	return that.Parser.GetAddr()
+}
+
+func (that *CTrojanOut) Port() int {
+	if that.Parser == nil {
+		return 0
+	}
+	return that.Parser.GetPort()
+}
+
+func (that *CTrojanOut) Scheme() string {
+	return parser.SchemeTrojan
+}
+
+func (that *CTrojanOut) GetRawUri() string {
+	return that.RawUri
+}
+
+func (that *CTrojanOut) getSettings() *gjson.Json {
+	j := gjson.New(ClashTrojan)
+	j.Set("name", utils.OutboundTag)
+	j.Set("server", that.Parser.Address)
+	j.Set("port", that.Parser.Port)
+	j.Set("password", that.Parser.Password)
+	j = PrepareStreamString(j, that.Parser.StreamField)
+	// trojan is always over TLS and names the server name "sni".
+	if sn := j.Get("servername").String(); sn != "" {
+		j.Set("sni", sn)
+	}
+	j.Remove("servername")
+	j.Remove("tls")
+	return j
+}
+
+func (that *CTrojanOut) GetOutboundStr() string {
+	if that.Parser.Address == "" && that.Parser.Port == 0 {
+		return ""
+	}
+	if that.outbound == "" {
+		that.outbound = ToProxiesYaml(that.getSettings())
+	}
+	return that.outbound
+}
+
+func TestTrojan() {
+	rawUri := "trojan://4d706727-996f-4427-930d-60f3bd414cf9@cnamemk.ciscocdn1.live:443?type=ws&sni=c2mk.ciscocdn1.live&allowInsecure=1&path=/rDCYQta83d0oPABKBhcIX#🇺🇸_US_美国->🇵🇱_PL_波兰"
+	to := &CTrojanOut{}
+	to.Parse(rawUri)
+	fmt.Println(to.GetOutboundStr())
+}
@@ -0,0 +1,101 @@
+package clash
+
+import (
+	"strings"
+
+	"github.com/ngwayzinmoe/uri-to-json/pkgs/parser"
+	"github.com/ngwayzinmoe/uri-to-json/pkgs/utils"
+	"github.com/gogf/gf/v2/encoding/gjson"
+	"github.com/gogf/gf/v2/util/gconv"
+)
+
+/*
+https://wiki.metacubex.one/config/proxies/tuic/
+
+- name: tuic
+  server: www.example.com
+  port: 10443
+  type: tuic
+  uuid: 00000000-0000-0000-0000-000000000001
+  password: PASSWORD_1
+  alpn: [h3]
+  disable-sni: true
+  reduce-rtt: true
+  udp-relay-mode: native
+  congestion-controller: bbr
+  sni: example.com
+  skip-cert-verify: false
+*/
+
+var ClashTUIC string = `{
+	"name": "tuic",
+	"type": "tuic",
+	"server": "127.0.0.1",
+	"port": 443,
+	"uuid": "",
+	"password": "",
+	"udp": true
+}`
+
+type CTUICOut struct {
+	RawUri   string
+	Parser   *parser.ParserTUIC
+	outbound string
+}
+
+func (that *CTUICOut) Parse(rawUri string) error {
+	that.Parser = &parser.ParserTUIC{}
+	return that.Parser.Parse(rawUri)
+}
+
+func (that *CTUICOut) Addr() string {
+	if that.Parser == nil {
+		return ""
+	}
+	return that.Parser.GetAddr()
+}
+
+func (that *CTUICOut) Port() int {
+	if that.Parser == nil {
+		return 0
+	}
+	return that.Parser.GetPort()
+}
+
+func (that *CTUICOut) Scheme() string {
+	return parser.SchemeTUIC
+}
+
+func (that *CTUICOut) GetRawUri() string {
+	return that.RawUri
+}
+
+func (that *CTUICOut) getSettings() *gjson.Json {
+	j := gjson.New(ClashTUIC)
+	j.Set("name", utils.OutboundTag)
+	j.Set("server", that.Parser.Address)
+	j.Set("port", that.Parser.Port)
+	j.Set("uuid", that.Parser.UUID)
+	j.Set("password", that.Parser.Password)
+	j.Set("congestion-controller", that.Parser.CongestionControl)
+	j.Set("udp-relay-mode", that.Parser.UDPRelayMode)
+	if that.Parser.TLSALPN != "" {
+		j.Set("alpn", strings.Split(that.Parser.TLSALPN, ","))
+	}
+	if that.Parser.ServerName != "" {
+		j.Set("sni", that.Parser.ServerName)
+	}
+	j.Set("skip-cert-verify", gconv.Bool(that.Parser.TLSAllowInsecure))
+	j.Set("disable-sni", that.Parser.DisableSNI)
+	return j
+}
+
+func (that *CTUICOut) GetOutboundStr() string {
+	if that.Parser.Address == "" && that.Parser.Port == 0 {
+		return ""
+	}
+	if that.outbound == "" {
+		that.outbound = ToProxiesYaml(that.getSettings())
+	}
+	return that.outbound
+}
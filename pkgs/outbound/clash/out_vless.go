@@ -0,0 +1,105 @@
+package clash
+
+import (
+	"fmt"
+
+	"github.com/ngwayzinmoe/uri-to-json/pkgs/parser"
+	"github.com/ngwayzinmoe/uri-to-json/pkgs/utils"
+	"github.com/gogf/gf/v2/encoding/gjson"
+)
+
+/*
+https://wiki.metacubex.one/config/proxies/vless/
+
+- name: "vless"
+  type: vless
+  server: server
+  port: 443
+  udp: true
+  uuid: uuid
+  flow: xtls-rprx-vision
+  packet-encoding: xudp
+  tls: true
+  servername: example.com
+  client-fingerprint: chrome
+  reality-opts:
+    public-key: xxx
+    short-id: xxx
+*/
+
+var ClashVless string = `{
+	"name": "vless",
+	"type": "vless",
+	"server": "127.0.0.1",
+	"port": 443,
+	"udp": true,
+	"uuid": ""
+}`
+
+type CVlessOut struct {
+	RawUri   string
+	Parser   *parser.ParserVless
+	outbound string
+}
+
+func (that *CVlessOut) Parse(rawUri string) error {
+	that.Parser = &parser.ParserVless{}
+	if err := that.Parser.Parse(rawUri); err != nil {
+		return err
+	}
+	return CheckNetwork(parser.SchemeVless, that.Parser.StreamField)
+}
+
+func (that *CVlessOut) Addr() string {
+	if that.Parser == nil {
+		return ""
+	}
+	return that.Parser.GetAddr()
+}
+
+func (that *CVlessOut) Port() int {
+	if that.Parser == nil {
+		return 0
+	}
+	return that.Parser.GetPort()
+}
+
+func (that *CVlessOut) Scheme() string {
+	return parser.SchemeVless
+}
+
+func (that *CVlessOut) GetRawUri() string {
+	return that.RawUri
+}
+
+func (that *CVlessOut) getSettings() *gjson.Json {
+	j := gjson.New(ClashVless)
+	j.Set("name", utils.OutboundTag)
+	j.Set("server", that.Parser.Address)
+	j.Set("port", that.Parser.Port)
+	j.Set("uuid", that.Parser.UUID)
+	if that.Parser.Flow != "" {
+		j.Set("flow", that.Parser.Flow)
+	}
+	if that.Parser.PacketEncoding != "" {
+		j.Set("packet-encoding", that.Parser.PacketEncoding)
+	}
+	return PrepareStreamString(j, that.Parser.StreamField)
+}
+
+func (that *CVlessOut) GetOutboundStr() string {
+	if that.Parser.Address == "" && that.Parser.Port == 0 {
+		return ""
+	}
+	if that.outbound == "" {
+		that.outbound = ToProxiesYaml(that.getSettings())
+	}
+	return that.outbound
+}
+
+func TestVless() {
+	rawUri := "vless://d572752d-b079-4169-a1a1-3da5721a8ab4@m2rel.siasepid.sbs:80?encryption=none&security=reality&sni=tgju.org&fp=firefox&pbk=HgrpXJzQo2liQMY9YAPq1_PuiDXNNBLx8hRyVVfUZko&sid=af41f983&spx=/&type=grpc&serviceName=@V2rayNGmat&mode=multi#德国_0828096"
+	vo := &CVlessOut{}
+	vo.Parse(rawUri)
+	fmt.Println(vo.GetOutboundStr())
+}
@@ -0,0 +1,106 @@
+package clash
+
+import (
+	"fmt"
+
+	"github.com/ngwayzinmoe/uri-to-json/pkgs/parser"
+	"github.com/ngwayzinmoe/uri-to-json/pkgs/utils"
+	"github.com/gogf/gf/v2/encoding/gjson"
+	"github.com/gogf/gf/v2/util/gconv"
+)
+
+/*
+https://wiki.metacubex.one/config/proxies/vmess/
+
+- name: "vmess"
+  type: vmess
+  server: server
+  port: 443
+  udp: true
+  uuid: uuid
+  alterId: 32
+  cipher: auto
+  packet-encoding: packetaddr
+  global-padding: false
+  authenticated-length: false
+*/
+
+var ClashVmess string = `{
+	"name": "vmess",
+	"type": "vmess",
+	"server": "127.0.0.1",
+	"port": 443,
+	"udp": true,
+	"uuid": "",
+	"alterId": 0,
+	"cipher": "auto"
+}`
+
+type CVmessOut struct {
+	RawUri   string
+	Parser   *parser.ParserVmess
+	outbound string
+}
+
+func (that *CVmessOut) Parse(rawUri string) error {
+	that.Parser = &parser.ParserVmess{}
+	if err := that.Parser.Parse(rawUri); err != nil {
+		return err
+	}
+	return CheckNetwork(parser.SchemeVmess, that.Parser.StreamField)
+}
+
+func (that *CVmessOut) Addr() string {
+	if that.Parser == nil {
+		return ""
+	}
+	return that.Parser.GetAddr()
+}
+
+func (that *CVmessOut) Port() int {
+	if that.Parser == nil {
+		return 0
+	}
+	return that.Parser.GetPort()
+}
+
+func (that *CVmessOut) Scheme() string {
+	return parser.SchemeVmess
+}
+
+func (that *CVmessOut) GetRawUri() string {
+	return that.RawUri
+}
+
+func (that *CVmessOut) getSettings() *gjson.Json {
+	j := gjson.New(ClashVmess)
+	j.Set("name", utils.OutboundTag)
+	j.Set("server", that.Parser.Address)
+	j.Set("port", that.Parser.Port)
+	j.Set("uuid", that.Parser.UUID)
+	j.Set("alterId", gconv.Int(that.Parser.AID))
+	if that.Parser.Security != "" {
+		j.Set("cipher", that.Parser.Security)
+	}
+	if that.Parser.PacketEncoding != "" {
+		j.Set("packet-encoding", that.Parser.PacketEncoding)
+	}
+	return PrepareStreamString(j, that.Parser.StreamField)
+}
+
+func (that *CVmessOut) GetOutboundStr() string {
+	if that.Parser.Address == "" && that.Parser.Port == 0 {
+		return ""
+	}
+	if that.outbound == "" {
+		that.outbound = ToProxiesYaml(that.getSettings())
+	}
+	return that.outbound
+}
+
+func TestVmess() {
+	rawUri := "vmess://{\"add\":\"bobbykotick.rip\",\"host\":\"Kansas.bobbykotick.rip\",\"sni\":\"Kansas.bobbykotick.rip\",\"id\":\"D213ED80-199B-4A01-9D62-BBCBA9C16226\",\"net\":\"ws\",\"path\":\"\\/speedtest\",\"port\":\"443\",\"ps\":\"GetAFreeNode.com-Kansas\",\"tls\":\"tls\",\"fp\":\"android\",\"alpn\":\"h2,http\\/1.1\",\"v\":2,\"aid\":0,\"type\":\"none\"}"
+	vo := &CVmessOut{}
+	vo.Parse(rawUri)
+	fmt.Println(vo.GetOutboundStr())
+}
@@ -0,0 +1,141 @@
+package clash
+
+import (
+	"strings"
+
+	"github.com/gogf/gf/v2/encoding/gjson"
+	"github.com/gogf/gf/v2/util/gconv"
+	"github.com/ngwayzinmoe/uri-to-json/pkgs/parser"
+)
+
+/*
+https://wiki.metacubex.one/config/proxies/transport/
+https://wiki.metacubex.one/config/proxies/tls/
+
+Supports:
+- TCP(none/http) / WS / gRPC
+- TLS / Reality, client-fingerprint, alpn
+*/
+
+// Networks clash has no transport for.
+var unsupportedNetworks = map[string]struct{}{
+	"kcp":   {},
+	"quic":  {},
+	"xhttp": {},
+}
+
+// CheckNetwork reports transports that clash can't emit.
+func CheckNetwork(scheme string, sf *parser.StreamField) error {
+	if _, ok := unsupportedNetworks[sf.Network]; ok {
+		return parser.NewParseError(parser.ErrUnsupportedTransport, scheme, "type", sf.Network)
+	}
+	return nil
+}
+
+// PrepareStreamString sets the transport and TLS options of a Clash proxy.
+func PrepareStreamString(cnf *gjson.Json, sf *parser.StreamField) *gjson.Json {
+	if cnf == nil || sf == nil {
+		return cnf
+	}
+
+	// ---------------- Network Transport ----------------
+	switch sf.Network {
+	case "tcp", "":
+		if sf.TCPHeaderType == "http" {
+			cnf.Set("network", "http")
+			path := sf.Path
+			if path == "" {
+				path = "/"
+			}
+			opts := map[string]interface{}{
+				"method": "GET",
+				"path":   []string{path},
+			}
+			if sf.Host != "" {
+				opts["headers"] = map[string]interface{}{
+					"Host": strings.Split(sf.Host, ","),
+				}
+			}
+			cnf.Set("http-opts", opts)
+		} else {
+			cnf.Set("network", "tcp")
+		}
+	case "ws":
+		cnf.Set("network", "ws")
+		path := sf.Path
+		if path == "" {
+			path = "/"
+		}
+		opts := map[string]interface{}{
+			"path": path,
+		}
+		if sf.Host != "" {
+			opts["headers"] = map[string]string{"Host": sf.Host}
+		}
+		cnf.Set("ws-opts", opts)
+	case "grpc":
+		cnf.Set("network", "grpc")
+		cnf.Set("grpc-opts", map[string]string{
+			"grpc-service-name": sf.GRPCServiceName,
+		})
+	case "httpupgrade":
+		cnf.Set("network", "ws")
+		path := sf.Path
+		if path == "" {
+			path = "/"
+		}
+		opts := map[string]interface{}{
+			"path":               path,
+			"v2ray-http-upgrade": true,
+		}
+		if sf.Host != "" {
+			opts["headers"] = map[string]string{"Host": sf.Host}
+		}
+		cnf.Set("ws-opts", opts)
+	case "http":
+		cnf.Set("network", "h2")
+		path := sf.Path
+		if path == "" {
+			path = "/"
+		}
+		opts := map[string]interface{}{
+			"path": path,
+		}
+		if sf.Host != "" {
+			opts["host"] = strings.Split(sf.Host, ",")
+		}
+		cnf.Set("h2-opts", opts)
+	default:
+		cnf.Set("network", sf.Network)
+	}
+
+	// ---------------- Security ----------------
+	if sf.StreamSecurity != "tls" && sf.StreamSecurity != "reality" {
+		return cnf
+	}
+	cnf.Set("tls", true)
+	sn := sf.ServerName
+	if sn == "" {
+		sn = sf.Host
+	}
+	if sn != "" {
+		cnf.Set("servername", sn)
+	}
+	cnf.Set("skip-cert-verify", gconv.Bool(sf.TLSAllowInsecure))
+	if sf.TLSALPN != "" {
+		cnf.Set("alpn", strings.Split(sf.TLSALPN, ","))
+	}
+	if sf.Fingerprint != "" {
+		cnf.Set("client-fingerprint", sf.Fingerprint)
+	}
+	if sf.StreamSecurity == "reality" {
+		cnf.Set("reality-opts", map[string]string{
+			"public-key": sf.RealityPublicKey,
+			"short-id":   sf.RealityShortId,
+		})
+		if sf.Fingerprint == "" {
+			cnf.Set("client-fingerprint", "chrome")
+		}
+	}
+	return cnf
+}
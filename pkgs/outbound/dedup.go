@@ -0,0 +1,120 @@
+package outbound
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/ngwayzinmoe/uri-to-json/pkgs/utils"
+	"github.com/gogf/gf/v2/encoding/gjson"
+)
+
+// credentialFields are the parsed fields identifying the user of a node, hysteria2 keeps them in Config.
+var credentialFields = []string{"Method", "UUID", "Password", "Auth", "Config.auth", "PrivateKey", "PublicKey"}
+
+func firstField(fields *gjson.Json, keys ...string) string {
+	for _, key := range keys {
+		if v := fields.Get(key).String(); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// normalizeHost lower cases host, and prints ips in their shortest form.
+func normalizeHost(host string) string {
+	host = strings.TrimSuffix(strings.ToLower(strings.Trim(host, "[]")), ".")
+	if ip := net.ParseIP(host); ip != nil {
+		return ip.String()
+	}
+	return host
+}
+
+/*
+Identity returns the canonical identity of item: scheme, host, port, credential, transport, path and sni.
+It is built from the parsed fields, so links only differing by remark, query order or base64 padding share it.
+*/
+func (that *ProxyItem) Identity() string {
+	if that.Address == "" {
+		that.GetOutbound()
+	}
+	fields := that.GetParsedFields()
+	if len(fields.Map()) == 0 {
+		// unparsable nodes are only equal to themselves.
+		return that.RawUri
+	}
+
+	host := normalizeHost(that.Address)
+	credential := []string{}
+	for _, key := range credentialFields {
+		if v := fields.Get(key).String(); v != "" {
+			credential = append(credential, v)
+		}
+	}
+	network := firstField(fields, "Network", "StreamField.Network")
+	if network == "" {
+		network = "tcp"
+	}
+	path := firstField(fields, "Path", "GRPCServiceName", "StreamField.Path")
+	if path == "/" {
+		path = ""
+	}
+	sni := strings.ToLower(firstField(fields, "ServerName", "Config.sni", "StreamField.ServerName"))
+	if sni == host {
+		// an empty sni falls back to the host.
+		sni = ""
+	}
+	return strings.Join([]string{
+		utils.ParseScheme(that.RawUri),
+		net.JoinHostPort(host, fmt.Sprint(that.Port)),
+		strings.Join(credential, ":"),
+		network,
+		path,
+		sni,
+	}, "|")
+}
+
+// DedupReport counts the duplicates dropped by Result.Dedup per scheme.
+type DedupReport map[string]int
+
+func (that DedupReport) Total() (total int) {
+	for _, count := range that {
+		total += count
+	}
+	return
+}
+
+// better reports whether a has a lower rtt than b, nodes not probed (0) or unreachable (-1) never win.
+func better(a, b *ProxyItem) bool {
+	return a.RTT > 0 && (b.RTT <= 0 || a.RTT < b.RTT)
+}
+
+/*
+Dedup drops the nodes sharing the same Identity.
+The node with the lowest rtt is kept in place of the first one seen, or the first one when none was probed.
+*/
+func (that *Result) Dedup() DedupReport {
+	report := DedupReport{}
+	kept := []*ProxyItem{}
+	index := map[string]int{}
+	for _, item := range that.GetTotalList() {
+		id := item.Identity()
+		i, ok := index[id]
+		if !ok {
+			index[id] = len(kept)
+			kept = append(kept, item)
+			continue
+		}
+		report[utils.ParseScheme(item.RawUri)]++
+		if better(item, kept[i]) {
+			kept[i] = item
+		}
+	}
+	if report.Total() > 0 {
+		that.Clear()
+		for _, item := range kept {
+			that.AddItem(item)
+		}
+	}
+	return report
+}
@@ -0,0 +1,410 @@
+package outbound
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/ngwayzinmoe/uri-to-json/pkgs/utils"
+)
+
+/*
+Filter expressions select nodes of a Result, eg.
+
+	scheme=vless && security=reality && port=443 && location!=CN && rtt<300
+	(scheme=trojan,vless || network=grpc) and not name~"(?i)expire"
+	host$=.example.com
+
+Conditions are "field op value", combined with &&/and, ||/or, !/not and parentheses.
+
+	fields:    scheme, port, network, security, location (country), rtt, name, host (address), sni, group
+	operators: = (==), != take a comma separated list of values and ignore case,
+	           <, <=, >, >= compare port and rtt, ~ and !~ match a regexp, $= matches a suffix.
+
+Values are bare words, or quoted with " or ' when they hold spaces, parentheses, & or |.
+Nodes not probed (0) or unreachable (-1) never match an rtt condition.
+*/
+type Filter struct {
+	expr   string
+	root   filterNode
+	fields map[string]bool
+}
+
+type filterNode interface {
+	match(item *ProxyItem) bool
+}
+
+type andNode struct{ left, right filterNode }
+
+func (that *andNode) match(item *ProxyItem) bool { return that.left.match(item) && that.right.match(item) }
+
+type orNode struct{ left, right filterNode }
+
+func (that *orNode) match(item *ProxyItem) bool { return that.left.match(item) || that.right.match(item) }
+
+type notNode struct{ node filterNode }
+
+func (that *notNode) match(item *ProxyItem) bool { return !that.node.match(item) }
+
+var filterFieldAliases = map[string]string{
+	"scheme":   "scheme",
+	"port":     "port",
+	"network":  "network",
+	"security": "security",
+	"location": "location",
+	"country":  "location",
+	"rtt":      "rtt",
+	"name":     "name",
+	"host":     "host",
+	"address":  "host",
+	"sni":      "sni",
+	"group":    "group",
+}
+
+var numericFilterFields = map[string]bool{"port": true, "rtt": true}
+
+// longer operators first, so "<=" isn't read as "<".
+var filterOperators = []string{"==", "!=", "<=", ">=", "!~", "$=", "=", "<", ">", "~"}
+
+type condNode struct {
+	field  string
+	op     string
+	values []string
+	num    int64
+	re     *regexp.Regexp
+}
+
+func newCondNode(field, op, value string) (*condNode, error) {
+	c := &condNode{field: field, op: op}
+	if op == "==" {
+		c.op = "="
+	}
+	if numericFilterFields[field] {
+		switch c.op {
+		case "=", "!=", "<", "<=", ">", ">=":
+		default:
+			return nil, fmt.Errorf("operator %s doesn't apply to %s", op, field)
+		}
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s needs a number: %s", field, value)
+		}
+		c.num = n
+		return c, nil
+	}
+
+	switch c.op {
+	case "~", "!~":
+		re, err := regexp.Compile(value)
+		if err != nil {
+			return nil, err
+		}
+		c.re = re
+	case "=", "!=", "$=":
+		for _, v := range strings.Split(value, ",") {
+			if v = strings.TrimSpace(v); v != "" || len(c.values) == 0 {
+				if field == "scheme" {
+					v = normalizeFilterScheme(v)
+				}
+				c.values = append(c.values, v)
+			}
+		}
+	default:
+		return nil, fmt.Errorf("operator %s doesn't apply to %s", op, field)
+	}
+	return c, nil
+}
+
+// normalizeFilterScheme turns "vless", "vless://" and "hy2" into "vless" and "hysteria2".
+func normalizeFilterScheme(s string) string {
+	return strings.TrimSuffix(utils.ParseScheme(strings.TrimSuffix(strings.ToLower(s), "://")+"://"), "://")
+}
+
+func (that *condNode) match(item *ProxyItem) bool {
+	if numericFilterFields[that.field] {
+		v := int64(item.Port)
+		if that.field == "rtt" {
+			if v = item.RTT; v <= 0 {
+				return false
+			}
+		}
+		switch that.op {
+		case "=":
+			return v == that.num
+		case "!=":
+			return v != that.num
+		case "<":
+			return v < that.num
+		case "<=":
+			return v <= that.num
+		case ">":
+			return v > that.num
+		case ">=":
+			return v >= that.num
+		}
+		return false
+	}
+
+	s := filterValue(item, that.field)
+	switch that.op {
+	case "~":
+		return that.re.MatchString(s)
+	case "!~":
+		return !that.re.MatchString(s)
+	case "$=":
+		for _, v := range that.values {
+			if strings.HasSuffix(strings.ToLower(s), strings.ToLower(v)) {
+				return true
+			}
+		}
+		return false
+	}
+	in := false
+	for _, v := range that.values {
+		if strings.EqualFold(s, v) {
+			in = true
+			break
+		}
+	}
+	return in == (that.op == "=")
+}
+
+// filterValue returns the string value of field for item, taken from its parsed fields when needed.
+func filterValue(item *ProxyItem, field string) string {
+	switch field {
+	case "scheme":
+		return strings.TrimSuffix(utils.ParseScheme(item.RawUri), "://")
+	case "location":
+		return item.Location
+	case "name":
+		return item.Name
+	case "group":
+		return item.Group
+	case "host":
+		if item.Address == "" {
+			item.GetOutbound()
+		}
+		return item.Address
+	}
+	fields := item.GetParsedFields()
+	switch field {
+	case "network":
+		if network := firstField(fields, "Network", "StreamField.Network"); network != "" {
+			return network
+		}
+		return "tcp"
+	case "security":
+		if security := firstField(fields, "StreamSecurity", "StreamField.StreamSecurity"); security != "" {
+			return security
+		}
+		return "none"
+	case "sni":
+		return firstField(fields, "ServerName", "Config.sni", "StreamField.ServerName")
+	}
+	return ""
+}
+
+// ParseFilter compiles a filter expression, see Filter for the syntax.
+func ParseFilter(expr string) (*Filter, error) {
+	p := &filterParser{input: []rune(expr), fields: map[string]bool{}}
+	root, err := p.parseOr()
+	if err == nil {
+		if p.skipSpaces(); p.pos < len(p.input) {
+			err = p.errorf("unexpected %q", string(p.input[p.pos:]))
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &Filter{expr: expr, root: root, fields: p.fields}, nil
+}
+
+func (that *Filter) String() string {
+	return that.expr
+}
+
+// Uses reports whether the expression has a condition on field, eg. "rtt" or "location".
+func (that *Filter) Uses(field string) bool {
+	if f, ok := filterFieldAliases[field]; ok {
+		field = f
+	}
+	return that.fields[field]
+}
+
+func (that *Filter) Match(item *ProxyItem) bool {
+	if that == nil || that.root == nil {
+		return true
+	}
+	return that.root.match(item)
+}
+
+// Filter returns a new Result holding the nodes matched by f.
+func (that *Result) Filter(f *Filter) *Result {
+	result := NewResult()
+	result.UpdateAt = that.UpdateAt
+	for _, item := range that.GetTotalList() {
+		if f.Match(item) {
+			result.AddItem(item)
+		}
+	}
+	return result
+}
+
+type filterParser struct {
+	input  []rune
+	pos    int
+	fields map[string]bool
+}
+
+func (that *filterParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("filter: at %d: %s", that.pos, fmt.Sprintf(format, args...))
+}
+
+func (that *filterParser) skipSpaces() {
+	for that.pos < len(that.input) && unicode.IsSpace(that.input[that.pos]) {
+		that.pos++
+	}
+}
+
+// consume skips tok, or the keyword word followed by a space or "(".
+func (that *filterParser) consume(tok, word string) bool {
+	that.skipSpaces()
+	rest := string(that.input[that.pos:])
+	if strings.HasPrefix(rest, tok) {
+		that.pos += len([]rune(tok))
+		return true
+	}
+	if len(rest) > len(word) && strings.EqualFold(rest[:len(word)], word) {
+		if next := rune(rest[len(word)]); unicode.IsSpace(next) || next == '(' {
+			that.pos += len([]rune(word))
+			return true
+		}
+	}
+	return false
+}
+
+func (that *filterParser) parseOr() (filterNode, error) {
+	left, err := that.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for that.consume("||", "or") {
+		right, err := that.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &orNode{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (that *filterParser) parseAnd() (filterNode, error) {
+	left, err := that.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for that.consume("&&", "and") {
+		right, err := that.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &andNode{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (that *filterParser) parseUnary() (filterNode, error) {
+	if that.consume("!", "not") {
+		node, err := that.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &notNode{node: node}, nil
+	}
+	if that.consume("(", "(") {
+		node, err := that.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !that.consume(")", ")") {
+			return nil, that.errorf("missing )")
+		}
+		return node, nil
+	}
+	return that.parseCond()
+}
+
+func (that *filterParser) parseCond() (filterNode, error) {
+	that.skipSpaces()
+	start := that.pos
+	for that.pos < len(that.input) && (unicode.IsLetter(that.input[that.pos]) || that.input[that.pos] == '_') {
+		that.pos++
+	}
+	name := strings.ToLower(string(that.input[start:that.pos]))
+	if name == "" {
+		return nil, that.errorf("field expected")
+	}
+	field, ok := filterFieldAliases[name]
+	if !ok {
+		return nil, that.errorf("unknown field %s", name)
+	}
+
+	that.skipSpaces()
+	op := ""
+	for _, o := range filterOperators {
+		if strings.HasPrefix(string(that.input[that.pos:]), o) {
+			op = o
+			break
+		}
+	}
+	if op == "" {
+		return nil, that.errorf("operator expected after %s", name)
+	}
+	that.pos += len(op)
+
+	value, err := that.parseValue()
+	if err != nil {
+		return nil, err
+	}
+	c, err := newCondNode(field, op, value)
+	if err != nil {
+		return nil, that.errorf("%s", err)
+	}
+	that.fields[field] = true
+	return c, nil
+}
+
+// parseValue reads a quoted value, or a bare one up to a space, a parenthesis, "&" or "|".
+func (that *filterParser) parseValue() (string, error) {
+	that.skipSpaces()
+	if that.pos < len(that.input) && (that.input[that.pos] == '"' || that.input[that.pos] == '\'') {
+		quote := that.input[that.pos]
+		var b strings.Builder
+		for that.pos++; that.pos < len(that.input); that.pos++ {
+			r := that.input[that.pos]
+			if r == '\\' && that.pos+1 < len(that.input) && that.input[that.pos+1] == quote {
+				that.pos++
+				r = quote
+			} else if r == quote {
+				that.pos++
+				return b.String(), nil
+			}
+			b.WriteRune(r)
+		}
+		return "", that.errorf("unterminated %c", quote)
+	}
+	start := that.pos
+	for that.pos < len(that.input) {
+		r := that.input[that.pos]
+		if unicode.IsSpace(r) || r == '(' || r == ')' || r == '&' || r == '|' {
+			break
+		}
+		that.pos++
+	}
+	if that.pos == start {
+		return "", that.errorf("value expected")
+	}
+	return string(that.input[start:that.pos]), nil
+}
@@ -0,0 +1,117 @@
+package outbound
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseFilterErrors(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"colour=red",
+		"scheme",
+		"port=abc",
+		"port~44",
+		"rtt$=0",
+		"name~(",
+		`name="unterminated`,
+		"(scheme=vless",
+		"scheme=vless)",
+		"scheme=vless &&",
+		"scheme=",
+	} {
+		if f, err := ParseFilter(expr); err == nil {
+			t.Errorf("%q: no error, parsed as %s", expr, f)
+		}
+	}
+}
+
+func TestFilterMatch(t *testing.T) {
+	items := map[string]*ProxyItem{
+		"reality": ParseRawUriToProxyItem("vless://uuid@a.example.com:443?type=grpc&security=reality&sni=www.apple.com&pbk=key#HK%2001", XrayCore),
+		"ws":      ParseRawUriToProxyItem("vless://uuid@b.example.com:8443?type=ws&security=tls&sni=b.example.com#US%20expire%202026", XrayCore),
+		"trojan":  ParseRawUriToProxyItem("trojan://pass@1.2.3.4:443?security=none#JP", XrayCore),
+		"hy2":     ParseRawUriToProxyItem("hy2://auth@h.example.org:443?sni=h.example.org#HK%2002", SingBox),
+	}
+	items["reality"].Location, items["reality"].RTT = "HK", 120
+	items["ws"].Location, items["ws"].RTT = "US", 450
+	items["trojan"].Location, items["trojan"].RTT = "JP", -1
+	items["hy2"].Location = "HK"
+
+	for _, c := range []struct {
+		expr string
+		want string
+	}{
+		{"scheme=vless", "reality ws"},
+		{"scheme=vless://,hy2", "hy2 reality ws"},
+		{"scheme!=vless", "hy2 trojan"},
+		{"security=reality && port=443", "reality"},
+		{"network=tcp", "trojan"},
+		{"network=grpc,ws", "reality ws"},
+		{"security=none", "trojan"},
+		{"port>=8443 || port<443", "ws"},
+		{"location=hk", "hy2 reality"},
+		{"country!=HK", "trojan ws"},
+		{"rtt<300", "reality"},
+		{"rtt>0", "reality ws"},
+		{"not rtt<300", "hy2 trojan ws"},
+		{`name~"(?i)expire"`, "ws"},
+		{"name!~^HK", "trojan ws"},
+		{"host$=.example.com", "reality ws"},
+		{"address=1.2.3.4", "trojan"},
+		{"sni=www.apple.com", "reality"},
+		{"sni$=example.org", "hy2"},
+		{"(scheme=trojan or location=US) and !name~JP", "ws"},
+		{"scheme == vless && (rtt < 200 || location = US)", "reality ws"},
+		{`name="HK 01"`, "reality"},
+	} {
+		f, err := ParseFilter(c.expr)
+		if err != nil {
+			t.Errorf("%q: %s", c.expr, err)
+			continue
+		}
+		got := []string{}
+		for _, key := range []string{"hy2", "reality", "trojan", "ws"} {
+			if f.Match(items[key]) {
+				got = append(got, key)
+			}
+		}
+		if strings.Join(got, " ") != c.want {
+			t.Errorf("%q matched %v, want %s", c.expr, got, c.want)
+		}
+	}
+}
+
+func TestFilterUses(t *testing.T) {
+	f, err := ParseFilter("country=US && rtt<300")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for field, want := range map[string]bool{"location": true, "country": true, "rtt": true, "scheme": false} {
+		if f.Uses(field) != want {
+			t.Errorf("Uses(%s) = %v, want %v", field, !want, want)
+		}
+	}
+	var none *Filter
+	if !none.Match(&ProxyItem{}) {
+		t.Error("a nil filter must match every node")
+	}
+}
+
+func TestResultFilter(t *testing.T) {
+	result := NewResult()
+	result.UpdateAt = "2026-01-02 03:04:05"
+	result.AddItem(ParseRawUriToProxyItem("vless://uuid@a.example.com:443?type=ws&security=tls#a", XrayCore))
+	result.AddItem(ParseRawUriToProxyItem("trojan://pass@b.example.com:443#b", XrayCore))
+	f, err := ParseFilter("scheme=trojan")
+	if err != nil {
+		t.Fatal(err)
+	}
+	filtered := result.Filter(f)
+	if filtered.Len() != 1 || filtered.TrojanTotal != 1 || filtered.UpdateAt != result.UpdateAt {
+		t.Errorf("filtered %d nodes, update at %q", filtered.Len(), filtered.UpdateAt)
+	}
+	if result.Len() != 2 {
+		t.Errorf("the filtered Result changed to %d nodes", result.Len())
+	}
+}
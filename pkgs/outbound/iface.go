@@ -1,7 +1,7 @@
 package outbound
 
 type IOutbound interface {
-	Parse(string)
+	Parse(string) error
 	Addr() string
 	Port() int
 	Scheme() string
@@ -0,0 +1,146 @@
+package outbound
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/oschwald/maxminddb-golang"
+)
+
+/*
+Offline GeoIP lookup with a local MaxMind format database, eg. GeoLite2-Country.mmdb,
+dbip-country-lite.mmdb or ipinfo country.mmdb.
+*/
+
+// Resolver resolves host names before the lookup, *net.Resolver fits.
+type Resolver interface {
+	LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error)
+}
+
+type mmdbCountry struct {
+	Country struct {
+		ISOCode string `maxminddb:"iso_code"`
+	} `maxminddb:"country"`
+	RegisteredCountry struct {
+		ISOCode string `maxminddb:"iso_code"`
+	} `maxminddb:"registered_country"`
+	// flat layout used by ipinfo.
+	CountryCode string `maxminddb:"country_code"`
+}
+
+type Locator struct {
+	Resolver Resolver
+	Timeout  time.Duration // resolve timeout of each host
+	Workers  int           // number of hosts located at the same time by Result.Locate
+	db       *maxminddb.Reader
+	cache    sync.Map
+}
+
+// NewLocator opens the mmdb file at dbPath, net.DefaultResolver is used unless resolver is given.
+func NewLocator(dbPath string, resolver ...Resolver) (*Locator, error) {
+	db, err := maxminddb.Open(dbPath)
+	if err != nil {
+		return nil, err
+	}
+	l := &Locator{
+		Resolver: net.DefaultResolver,
+		Timeout:  3 * time.Second,
+		Workers:  16,
+		db:       db,
+	}
+	if len(resolver) > 0 && resolver[0] != nil {
+		l.Resolver = resolver[0]
+	}
+	return l, nil
+}
+
+func (that *Locator) Close() error {
+	return that.db.Close()
+}
+
+// LookupIP returns the ISO country code of ip.
+func (that *Locator) LookupIP(ip net.IP) (string, error) {
+	record := &mmdbCountry{}
+	if err := that.db.Lookup(ip, record); err != nil {
+		return "", err
+	}
+	for _, code := range []string{record.Country.ISOCode, record.RegisteredCountry.ISOCode, record.CountryCode} {
+		if code != "" {
+			return strings.ToUpper(code), nil
+		}
+	}
+	return "", fmt.Errorf("no country found for %s", ip)
+}
+
+// Country resolves host when it is a domain and returns its ISO country code.
+func (that *Locator) Country(host string) (string, error) {
+	host = strings.Trim(host, "[]")
+	if code, ok := that.cache.Load(host); ok {
+		return code.(string), nil
+	}
+	ips := []net.IP{net.ParseIP(host)}
+	if ips[0] == nil {
+		ctx, cancel := context.WithTimeout(context.Background(), that.Timeout)
+		defer cancel()
+		addrs, err := that.Resolver.LookupIPAddr(ctx, host)
+		if err != nil {
+			return "", err
+		}
+		ips = ips[:0]
+		for _, addr := range addrs {
+			ips = append(ips, addr.IP)
+		}
+	}
+	// the first address known by the database wins.
+	err := fmt.Errorf("no address found for %s", host)
+	for _, ip := range ips {
+		var code string
+		if code, err = that.LookupIP(ip); err == nil {
+			that.cache.Store(host, code)
+			return code, nil
+		}
+	}
+	return "", err
+}
+
+// LocateItem fills the Location of item, it is left empty when the lookup fails.
+func (that *Locator) LocateItem(item *ProxyItem) string {
+	if item.Address == "" {
+		item.GetOutbound()
+	}
+	if item.Address == "" {
+		return ""
+	}
+	if code, err := that.Country(item.Address); err == nil {
+		item.Location = code
+	}
+	return item.Location
+}
+
+// Locate fills the Location of every node.
+func (that *Result) Locate(l *Locator) {
+	workers := l.Workers
+	if workers <= 0 {
+		workers = 1
+	}
+	items := make(chan *ProxyItem)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for item := range items {
+				l.LocateItem(item)
+			}
+		}()
+	}
+	for _, item := range that.GetTotalList() {
+		items <- item
+	}
+	close(items)
+	wg.Wait()
+}
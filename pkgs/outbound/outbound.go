@@ -1,8 +1,8 @@
 package outbound
 
 import (
-	"fmt"
-
+	"github.com/ngwayzinmoe/uri-to-json/pkgs/outbound/clash"
+	"github.com/ngwayzinmoe/uri-to-json/pkgs/outbound/sing"
 	"github.com/ngwayzinmoe/uri-to-json/pkgs/outbound/xray"
 	"github.com/ngwayzinmoe/uri-to-json/pkgs/parser"
 	"github.com/ngwayzinmoe/uri-to-json/pkgs/utils"
@@ -12,8 +12,11 @@ type ClientType string
 
 const (
 	XrayCore ClientType = "xray"
+	SingBox  ClientType = "sing"
+	Clash    ClientType = "clash"
 )
 
+// GetOutbound returns the outbound of clientType for rawUri, or nil when the client doesn't support its scheme.
 func GetOutbound(clientType ClientType, rawUri string) (result IOutbound) {
 	scheme := utils.ParseScheme(rawUri)
 	switch clientType {
@@ -29,11 +32,62 @@ func GetOutbound(clientType ClientType, rawUri string) (result IOutbound) {
 			result = &xray.ShadowSocksOut{RawUri: rawUri}
 		case parser.SchemeHysteria2: // [၁] Xray အတွက် Hysteria2 ကို Register လုပ်ခြင်း
 			result = &xray.Hysteria2Out{RawUri: rawUri}
-		default:
-			fmt.Println("unsupported protocol for Xray: ", scheme)
+		case parser.SchemeWireguard:
+			result = &xray.WireguardOut{RawUri: rawUri}
+		}
+	case SingBox:
+		switch scheme {
+		case parser.SchemeVmess:
+			result = &sing.SVmessOut{RawUri: rawUri}
+		case parser.SchemeVless:
+			result = &sing.SVlessOut{RawUri: rawUri}
+		case parser.SchemeTrojan:
+			result = &sing.STrojanOut{RawUri: rawUri}
+		case parser.SchemeSS:
+			result = &sing.SShadowSocksOut{RawUri: rawUri}
+		case parser.SchemeSSR:
+			result = &sing.SShadowSocksROut{RawUri: rawUri}
+		case parser.SchemeHysteria2:
+			result = &sing.SHysteria2Out{RawUri: rawUri}
+		case parser.SchemeHysteria:
+			result = &sing.SHysteriaOut{RawUri: rawUri}
+		case parser.SchemeWireguard:
+			result = &sing.SWireguardOut{RawUri: rawUri}
+		case parser.SchemeTUIC:
+			result = &sing.STUICOut{RawUri: rawUri}
+		}
+	case Clash:
+		switch scheme {
+		case parser.SchemeVmess:
+			result = &clash.CVmessOut{RawUri: rawUri}
+		case parser.SchemeVless:
+			result = &clash.CVlessOut{RawUri: rawUri}
+		case parser.SchemeTrojan:
+			result = &clash.CTrojanOut{RawUri: rawUri}
+		case parser.SchemeSS:
+			result = &clash.CShadowSocksOut{RawUri: rawUri}
+		case parser.SchemeSSR:
+			result = &clash.CShadowSocksROut{RawUri: rawUri}
+		case parser.SchemeHysteria2:
+			result = &clash.CHysteria2Out{RawUri: rawUri}
+		case parser.SchemeHysteria:
+			result = &clash.CHysteriaOut{RawUri: rawUri}
+		case parser.SchemeTUIC:
+			result = &clash.CTUICOut{RawUri: rawUri}
 		}
-	default:
-		fmt.Println("unsupported client type")
 	}
 	return
 }
+
+// ParseOutbound gets the outbound of clientType for rawUri and parses it.
+// Unsupported schemes are reported as parser.ErrInvalidScheme.
+func ParseOutbound(clientType ClientType, rawUri string) (IOutbound, error) {
+	ob := GetOutbound(clientType, rawUri)
+	if ob == nil {
+		return nil, parser.NewParseError(parser.ErrInvalidScheme, utils.ParseScheme(rawUri), "client", string(clientType))
+	}
+	if err := ob.Parse(rawUri); err != nil {
+		return nil, err
+	}
+	return ob, nil
+}
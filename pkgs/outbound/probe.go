@@ -0,0 +1,114 @@
+package outbound
+
+import (
+	"crypto/tls"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/ngwayzinmoe/uri-to-json/pkgs/parser"
+)
+
+// Schemes that run over udp, a tcp dial tells nothing about them.
+var SchemesOverUDP = []string{
+	parser.SchemeHysteria,
+	parser.SchemeHysteria2,
+	parser.SchemeTUIC,
+	parser.SchemeWireguard,
+}
+
+type ProbeOptions struct {
+	Workers int           // number of nodes probed at the same time
+	Timeout time.Duration // per node, including the tls handshake
+	TLS     bool          // complete a tls handshake for tls/reality nodes
+}
+
+func NewProbeOptions() *ProbeOptions {
+	return &ProbeOptions{
+		Workers: 32,
+		Timeout: 3 * time.Second,
+	}
+}
+
+func isOverUDP(scheme string) bool {
+	for _, s := range SchemesOverUDP {
+		if s == scheme {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+ProbeItem dials the host of item and returns the rtt in milliseconds, or -1 on failure.
+With opts.TLS, nodes using tls or reality also complete a handshake using the parsed server name.
+*/
+func ProbeItem(item *ProxyItem, opts *ProbeOptions) int64 {
+	if opts == nil {
+		opts = NewProbeOptions()
+	}
+	if item.GetHost() == "" {
+		item.GetOutbound()
+	}
+	host := item.GetHost()
+	if host == "" || isOverUDP(item.Scheme) {
+		return -1
+	}
+
+	start := time.Now()
+	conn, err := net.DialTimeout("tcp", host, opts.Timeout)
+	if err != nil {
+		return -1
+	}
+	defer conn.Close()
+
+	fields := item.GetParsedFields()
+	if security := fields.Get("StreamSecurity").String(); opts.TLS && (security == "tls" || security == "reality") {
+		sni := fields.Get("ServerName").String()
+		if sni == "" {
+			sni = item.Address
+		}
+		conn.SetDeadline(start.Add(opts.Timeout))
+		tlsConn := tls.Client(conn, &tls.Config{
+			ServerName: sni,
+			// only the latency matters, reality servers don't present a valid certificate anyway.
+			InsecureSkipVerify: true,
+		})
+		if err := tlsConn.Handshake(); err != nil {
+			return -1
+		}
+	}
+	rtt := time.Since(start).Milliseconds()
+	if rtt == 0 {
+		rtt = 1
+	}
+	return rtt
+}
+
+// Probe fills the RTT of every node with a pool of opts.Workers workers.
+func (that *Result) Probe(opts *ProbeOptions) {
+	if opts == nil {
+		opts = NewProbeOptions()
+	}
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = 1
+	}
+
+	items := make(chan *ProxyItem)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for item := range items {
+				item.RTT = ProbeItem(item, opts)
+			}
+		}()
+	}
+	for _, item := range that.GetTotalList() {
+		items <- item
+	}
+	close(items)
+	wg.Wait()
+}
@@ -0,0 +1,87 @@
+package outbound
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// listen accepts and closes connections on a random local port until the test ends.
+func listen(t *testing.T) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+	return ln.Addr().String()
+}
+
+// closedPort returns an address nothing listens on.
+func closedPort(t *testing.T) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+	return addr
+}
+
+func TestProbeItem(t *testing.T) {
+	open := listen(t)
+	closed := closedPort(t)
+	tlsServer := httptest.NewTLSServer(http.NotFoundHandler())
+	defer tlsServer.Close()
+	tlsAddr := strings.TrimPrefix(tlsServer.URL, "https://")
+
+	for _, c := range []struct {
+		name      string
+		rawUri    string
+		tls       bool
+		reachable bool
+	}{
+		{"open", fmt.Sprintf("trojan://pass@%s?security=none#t", open), false, true},
+		{"closed", fmt.Sprintf("trojan://pass@%s?security=none#t", closed), false, false},
+		{"udp", fmt.Sprintf("tuic://uuid:pass@%s#tuic", open), false, false},
+		{"tls handshake", fmt.Sprintf("vless://uuid@%s?security=tls&sni=a.com#v", tlsAddr), true, true},
+		{"no tls", fmt.Sprintf("vless://uuid@%s?security=tls&sni=a.com#v", open), true, false},
+		{"tls not checked", fmt.Sprintf("vless://uuid@%s?security=tls&sni=a.com#v", open), false, true},
+	} {
+		item := ParseRawUriToProxyItem(c.rawUri)
+		opts := &ProbeOptions{Workers: 1, Timeout: time.Second, TLS: c.tls}
+		if rtt := ProbeItem(item, opts); (rtt > 0) != c.reachable || rtt == 0 {
+			t.Errorf("%s: rtt = %d, reachable %v", c.name, rtt, c.reachable)
+		}
+	}
+}
+
+func TestResultProbe(t *testing.T) {
+	open := listen(t)
+	closed := closedPort(t)
+	result := NewResult()
+	for i := 0; i < 5; i++ {
+		result.AddItem(ParseRawUriToProxyItem(fmt.Sprintf("trojan://pass%d@%s#open", i, open), XrayCore))
+		result.AddItem(ParseRawUriToProxyItem(fmt.Sprintf("trojan://pass%d@%s#closed", i, closed), XrayCore))
+	}
+	result.Probe(&ProbeOptions{Workers: 3, Timeout: time.Second})
+	for _, item := range result.GetTotalList() {
+		if reachable := item.Name == "open"; (item.RTT > 0) != reachable {
+			t.Errorf("%s: rtt = %d", item.RawUri, item.RTT)
+		}
+	}
+}
@@ -6,8 +6,11 @@ import (
 
 	"encoding/json"
 
+	"github.com/ngwayzinmoe/uri-to-json/pkgs/outbound/clash"
+	"github.com/ngwayzinmoe/uri-to-json/pkgs/outbound/xray"
 	"github.com/ngwayzinmoe/uri-to-json/pkgs/parser"
 	"github.com/ngwayzinmoe/uri-to-json/pkgs/utils"
+	"github.com/gogf/gf/v2/encoding/gjson"
 )
 
 var ShadowSocksMethodOnlyBySing = []string{
@@ -32,15 +35,28 @@ func EnableSingBox(rawUri string) bool {
 	return false
 }
 
+// isSingOnlyMethod reports whether the shadowsocks method is only implemented by sing-box.
+func isSingOnlyMethod(method string) bool {
+	for _, m := range ShadowSocksMethodOnlyBySing {
+		if method == m {
+			return true
+		}
+	}
+	return false
+}
+
 type ProxyItem struct {
 	Scheme       string     `json:"scheme"`
 	Address      string     `json:"address"`
 	Port         int        `json:"port"`
+	Name         string     `json:"name"`  // remark of the node
+	Group        string     `json:"group"` // only ssr links carry a group
 	RTT          int64      `json:"rtt"`
 	RawUri       string     `json:"raw_uri"`
 	Location     string     `json:"location"`
 	Outbound     string     `json:"outbound"`
 	OutboundType ClientType `json:"outbound_type"`
+	fields       *gjson.Json
 }
 
 func NewItem(rawUri string) *ProxyItem {
@@ -55,24 +71,69 @@ func NewItemByEncryptedRawUri(enRawUri string) (item *ProxyItem) {
 	return &ProxyItem{RawUri: rawUri}
 }
 
-func (that *ProxyItem) parse() bool {
-	that.Scheme = utils.ParseScheme(that.RawUri)
-	var ob IOutbound
-	if that.Scheme == parser.SchemeSSR || (that.Scheme == parser.SchemeSS && strings.Contains(that.RawUri, "plugin=")) {
-		that.OutboundType = XrayCore
-		ob = GetOutbound(XrayCore, that.RawUri)
+// NewItemByOutbound returns the ProxyItem of an outbound already parsed for clientType.
+func NewItemByOutbound(ob IOutbound, clientType ClientType) *ProxyItem {
+	p := &ProxyItem{
+		Scheme:       ob.Scheme(),
+		Address:      ob.Addr(),
+		Port:         ob.Port(),
+		RawUri:       ob.GetRawUri(),
+		Outbound:     ob.GetOutboundStr(),
+		OutboundType: clientType,
 	}
+	p.setFields(ob)
+	return p
+}
+
+// Schemes xray-core can't speak, sing-box is used for them instead.
+var SchemesOnlyBySing = []string{
+	parser.SchemeSSR,
+	parser.SchemeTUIC,
+	parser.SchemeHysteria,
+}
 
+// GetCapableClientType picks a client that supports rawUri, xray-core is preferred.
+func GetCapableClientType(rawUri string) ClientType {
+	scheme := utils.ParseScheme(rawUri)
+	for _, s := range SchemesOnlyBySing {
+		if scheme == s {
+			return SingBox
+		}
+	}
+	if scheme == parser.SchemeSS {
+		// the method is hidden in base64, and a remark may mention another one.
+		p := &parser.ParserSS{}
+		if p.Parse(rawUri) == nil && (isSingOnlyMethod(p.Method) || xray.CheckSSPlugin(p) != nil) {
+			return SingBox
+		}
+	}
+	return XrayCore
+}
+
+func (that *ProxyItem) parse() bool {
+	that.Scheme = utils.ParseScheme(that.RawUri)
+	that.OutboundType = GetCapableClientType(that.RawUri)
+	ob := GetOutbound(that.OutboundType, that.RawUri)
 	if ob == nil {
 		return false
 	}
-	ob.Parse(that.RawUri)
+	if err := ob.Parse(that.RawUri); err != nil {
+		return false
+	}
 	that.Outbound = ob.GetOutboundStr()
 	that.Address = ob.Addr()
 	that.Port = ob.Port()
+	that.setFields(ob)
 	return true
 }
 
+// setFields keeps the parsed fields of ob, and the name and group found in them.
+func (that *ProxyItem) setFields(ob IOutbound) {
+	that.fields = ParsedFields(ob)
+	that.Name = that.fields.Get("Name").String()
+	that.Group = that.fields.Get("Group").String()
+}
+
 // Item string for conf.txt
 func (that *ProxyItem) String() string {
 	if that.Outbound == "" {
@@ -104,7 +165,30 @@ func (that *ProxyItem) GetOutboundType() ClientType {
 	return that.OutboundType
 }
 
-// Automatically parse rawUri to ProxyItem for certain Client[sing-box/xray-core]
+// ParsedFields returns the exported fields of the parser behind ob, eg. Address, Network, ServerName.
+func ParsedFields(ob IOutbound) *gjson.Json {
+	// every outbound keeps its parser in the exported Parser field.
+	if content, err := json.Marshal(ob); err == nil {
+		if p := gjson.New(content).GetJson("Parser"); p != nil {
+			return p
+		}
+	}
+	return gjson.New("{}")
+}
+
+// GetParsedFields parses RawUri and returns the fields of its parser, see ParsedFields.
+func (that *ProxyItem) GetParsedFields() *gjson.Json {
+	if that.fields == nil {
+		that.fields = gjson.New("{}")
+		if ob, err := ParseOutbound(GetCapableClientType(that.RawUri), that.RawUri); err == nil {
+			that.fields = ParsedFields(ob)
+		}
+	}
+	return that.fields
+}
+
+// Automatically parse rawUri to ProxyItem for certain Client[sing-box/xray-core/clash].
+// Without clientType a capable client is picked by GetCapableClientType.
 func ParseRawUriToProxyItem(rawUri string, clientType ...ClientType) (p *ProxyItem) {
 	if len(clientType) == 0 {
 		p = NewItem(rawUri)
@@ -113,21 +197,61 @@ func ParseRawUriToProxyItem(rawUri string, clientType ...ClientType) (p *ProxyIt
 	}
 	p = NewItem(rawUri)
 	p.Scheme = utils.ParseScheme(p.RawUri)
-		p.OutboundType = XrayCore
-		ob := GetOutbound(XrayCore, p.RawUri)
-		if ob == nil {
-			return
-		}
-		ob.Parse(p.RawUri)
-		p.Outbound = ob.GetOutboundStr()
-		p.Address = ob.Addr()
-		p.Port = ob.Port()
+	p.OutboundType = clientType[0]
+	ob, err := ParseOutbound(p.OutboundType, p.RawUri)
+	if err != nil {
 		return
+	}
+	p.Outbound = ob.GetOutboundStr()
+	p.Address = ob.Addr()
+	p.Port = ob.Port()
+	p.setFields(ob)
+	return
 }
 
 func ParseEncryptedRawUriToProxyItem(rawUri string, clientType ...ClientType) (p *ProxyItem) {
 	rawUri = parser.ParseRawUri(rawUri)
-	return ParseRawUriToProxyItem(rawUri)
+	return ParseRawUriToProxyItem(rawUri, clientType...)
+}
+
+// SetOutboundTag replaces the tag of an outbound string, or the name of a clash proxy.
+func SetOutboundTag(clientType ClientType, oStr, tag string) string {
+	if oStr == "" || tag == "" {
+		return oStr
+	}
+	if clientType == Clash {
+		j, err := gjson.LoadYaml(oStr)
+		if err != nil || j.GetJson("proxies.0") == nil {
+			return oStr
+		}
+		proxy := j.GetJson("proxies.0")
+		proxy.Set("name", tag)
+		return clash.ToProxiesYaml(proxy)
+	}
+	j := gjson.New(oStr)
+	if j == nil || j.IsNil() {
+		return oStr
+	}
+	j.Set("tag", tag)
+	return j.MustToJsonString()
+}
+
+// UniqueTags turns node names into outbound tags, duplicates get a "-N" suffix and empty names are utils.OutboundTag.
+func UniqueTags(names []string) []string {
+	tags := make([]string, len(names))
+	used := map[string]bool{}
+	for i, name := range names {
+		if name == "" {
+			name = utils.OutboundTag
+		}
+		tag := name
+		for n := 1; used[tag]; n++ {
+			tag = fmt.Sprintf("%s-%d", name, n)
+		}
+		used[tag] = true
+		tags[i] = tag
+	}
+	return tags
 }
 
 // Transfer ProxyItem to specified ClientType: sing-box or xray-core
@@ -135,7 +259,7 @@ func TransferProxyItem(oldProxyItem *ProxyItem, clientType ...ClientType) (newPr
 	if oldProxyItem == nil {
 		return
 	}
-	newProxyItem = ParseRawUriToProxyItem(oldProxyItem.RawUri)
+	newProxyItem = ParseRawUriToProxyItem(oldProxyItem.RawUri, clientType...)
 	newProxyItem.Location = oldProxyItem.Location
 	newProxyItem.RTT = oldProxyItem.RTT
 	return
@@ -0,0 +1,28 @@
+package outbound
+
+import (
+	"encoding/base64"
+	"testing"
+)
+
+func TestGetCapableClientType(t *testing.T) {
+	b64 := base64.StdEncoding.EncodeToString
+	for _, c := range []struct {
+		rawUri string
+		want   ClientType
+	}{
+		{"ss://" + b64([]byte("aes-256-gcm:pass")) + "@1.2.3.4:8388#rc4-node", XrayCore},
+		{"ss://" + b64([]byte("aes-256-gcm:pass")) + "@1.2.3.4:8388#aes-256-cfb%20node", XrayCore},
+		{"ss://" + b64([]byte("rc4-md5:pass")) + "@1.2.3.4:8388#n", SingBox},
+		{"ss://" + b64([]byte("aes-128-ctr:pass@1.2.3.4:8388")) + "#legacy", SingBox},
+		{"ss://" + b64([]byte("chacha20-ietf-poly1305:pass")) + "@1.2.3.4:8388#n", XrayCore},
+		{"ss://" + b64([]byte("chacha20-ietf:pass")) + "@1.2.3.4:8388#n", SingBox},
+		{"ss://" + b64([]byte("aes-256-gcm:pass")) + "@1.2.3.4:8388?plugin=obfs-local%3Bobfs%3Dhttp#obfs", SingBox},
+		{"ssr://" + b64([]byte("1.2.3.4:8388:origin:aes-256-cfb:plain:" + b64([]byte("pass")) + "/?")), SingBox},
+		{"vless://uuid@example.com:443?type=ws#rc4-md5", XrayCore},
+	} {
+		if got := GetCapableClientType(c.rawUri); got != c.want {
+			t.Errorf("%s: %s, want %s", c.rawUri, got, c.want)
+		}
+	}
+}
@@ -6,6 +6,7 @@ import (
 
 	"encoding/json"
 
+	"github.com/ngwayzinmoe/uri-to-json/pkgs/outbound/xray"
 	"github.com/ngwayzinmoe/uri-to-json/pkgs/parser"
 	"github.com/ngwayzinmoe/uri-to-json/pkgs/utils"
 	"github.com/gvcgo/goutils/pkgs/gutils"
@@ -17,14 +18,20 @@ type Result struct {
 	ShadowSocks    []*ProxyItem `json:"Shadowsocks"`
 	ShadowSocksR   []*ProxyItem `json:"ShadowsocksR"`
 	Trojan         []*ProxyItem `json:"Trojan"`
+	Hysteria       []*ProxyItem `json:"Hysteria"`
 	Hysteria2      []*ProxyItem `json:"Hysteria2"` // [၁] Hysteria2 list အသစ်
+	TUIC           []*ProxyItem `json:"TUIC"`
+	WireGuard      []*ProxyItem `json:"WireGuard"`
 	UpdateAt       string       `json:"UpdateAt"`
 	VmessTotal     int          `json:"VmessTotal"`
 	VlessTotal     int          `json:"VlessTotal"`
 	TrojanTotal    int          `json:"TrojanTotal"`
 	SSTotal        int          `json:"SSTotal"`
 	SSRTotal       int          `json:"SSRTotal"`
+	HysteriaTotal  int          `json:"HysteriaTotal"`
 	Hysteria2Total int          `json:"Hysteria2Total"` // [၂] Total count field
+	TUICTotal      int          `json:"TUICTotal"`
+	WireGuardTotal int          `json:"WireGuardTotal"`
 	totalList      []*ProxyItem
 	lock           *sync.Mutex
 }
@@ -77,16 +84,25 @@ func (that *Result) AddItem(proxyItem *ProxyItem) {
 	case parser.SchemeSSR:
 		that.ShadowSocksR = append(that.ShadowSocksR, proxyItem)
 		that.SSRTotal++
+	case parser.SchemeHysteria:
+		that.Hysteria = append(that.Hysteria, proxyItem)
+		that.HysteriaTotal++
 	case parser.SchemeHysteria2: // [၃] Hysteria2 link ကို case ထည့်ခြင်း
 		that.Hysteria2 = append(that.Hysteria2, proxyItem)
 		that.Hysteria2Total++
+	case parser.SchemeTUIC:
+		that.TUIC = append(that.TUIC, proxyItem)
+		that.TUICTotal++
+	case parser.SchemeWireguard:
+		that.WireGuard = append(that.WireGuard, proxyItem)
+		that.WireGuardTotal++
 	default:
 	}
 	that.totalList = append(that.totalList, proxyItem)
 }
 
 func (that *Result) Len() int {
-	return that.VmessTotal + that.VlessTotal + that.TrojanTotal + that.SSTotal + that.SSRTotal + that.Hysteria2Total
+	return that.VmessTotal + that.VlessTotal + that.TrojanTotal + that.SSTotal + that.SSRTotal + that.HysteriaTotal + that.Hysteria2Total + that.TUICTotal + that.WireGuardTotal
 }
 
 func (that *Result) GetTotalList() []*ProxyItem {
@@ -98,7 +114,10 @@ func (that *Result) GetTotalList() []*ProxyItem {
 		that.totalList = append(that.totalList, that.Trojan...)
 		that.totalList = append(that.totalList, that.ShadowSocks...)
 		that.totalList = append(that.totalList, that.ShadowSocksR...)
+		that.totalList = append(that.totalList, that.Hysteria...)
 		that.totalList = append(that.totalList, that.Hysteria2...)
+		that.totalList = append(that.totalList, that.TUIC...)
+		that.totalList = append(that.totalList, that.WireGuard...)
 	}
 	return that.totalList
 }
@@ -117,7 +136,30 @@ func (that *Result) Clear() {
 	that.SSTotal = 0
 	that.ShadowSocksR = []*ProxyItem{}
 	that.SSRTotal = 0
+	that.Hysteria = []*ProxyItem{}
+	that.HysteriaTotal = 0
 	that.Hysteria2 = []*ProxyItem{} // [၄] Clear Hysteria2
 	that.Hysteria2Total = 0
+	that.TUIC = []*ProxyItem{}
+	that.TUICTotal = 0
+	that.WireGuard = []*ProxyItem{}
+	that.WireGuardTotal = 0
 	that.totalList = []*ProxyItem{}
 }
+
+// GetXrayBalancerConfig builds an xray config balancing between every node of the Result.
+// Nodes that were converted for another client are converted to xray-core first, the ones xray-core can't handle are skipped.
+func (that *Result) GetXrayBalancerConfig(opts *xray.ConfigOptions, bOpts *xray.BalancerOptions) string {
+	outbounds := []string{}
+	for _, item := range that.GetTotalList() {
+		if item.GetOutboundType() != XrayCore {
+			item = TransferProxyItem(item, XrayCore)
+		}
+		// GetOutbound would fall back to another client for the schemes xray can't handle.
+		if item.OutboundType != XrayCore || item.Outbound == "" {
+			continue
+		}
+		outbounds = append(outbounds, item.Outbound)
+	}
+	return xray.GetBalancerConfig(outbounds, opts, bOpts)
+}
@@ -0,0 +1,108 @@
+package sing
+
+import (
+	"github.com/ngwayzinmoe/uri-to-json/pkgs/parser"
+	"github.com/ngwayzinmoe/uri-to-json/pkgs/utils"
+	"github.com/gogf/gf/v2/encoding/gjson"
+)
+
+/*
+https://sing-box.sagernet.org/configuration/outbound/hysteria/
+
+{
+  "type": "hysteria",
+  "tag": "hysteria-out",
+
+  "server": "127.0.0.1",
+  "server_port": 1080,
+  "up": "100 Mbps",
+  "up_mbps": 100,
+  "down": "100 Mbps",
+  "down_mbps": 100,
+  "obfs": "fuck me till the daylight",
+  "auth": "",
+  "auth_str": "password",
+  "recv_window_conn": 0,
+  "recv_window": 0,
+  "disable_mtu_discovery": false,
+  "network": "tcp",
+  "tls": {},
+
+  ... // Dial Fields
+}
+*/
+
+var SingHysteria string = `{
+	"type": "hysteria",
+	"tag": "hysteria-out",
+	"server": "127.0.0.1",
+	"server_port": 1080,
+	"up_mbps": 100,
+	"down_mbps": 100
+}`
+
+type SHysteriaOut struct {
+	RawUri   string
+	Parser   *parser.ParserHysteria
+	outbound string
+}
+
+func (that *SHysteriaOut) Parse(rawUri string) error {
+	that.Parser = &parser.ParserHysteria{}
+	return that.Parser.Parse(rawUri)
+}
+
+func (that *SHysteriaOut) Addr() string {
+	if that.Parser == nil {
+		return ""
+	}
+	return that.Parser.GetAddr()
+}
+
+func (that *SHysteriaOut) Port() int {
+	if that.Parser == nil {
+		return 0
+	}
+	return that.Parser.GetPort()
+}
+
+func (that *SHysteriaOut) Scheme() string {
+	return parser.SchemeHysteria
+}
+
+func (that *SHysteriaOut) GetRawUri() string {
+	return that.RawUri
+}
+
+func (that *SHysteriaOut) getSettings() string {
+	j := gjson.New(SingHysteria)
+	j.Set("server", that.Parser.Address)
+	j.Set("server_port", that.Parser.Port)
+	if that.Parser.UpMbps > 0 {
+		j.Set("up_mbps", that.Parser.UpMbps)
+	}
+	if that.Parser.DownMbps > 0 {
+		j.Set("down_mbps", that.Parser.DownMbps)
+	}
+	if that.Parser.Auth != "" {
+		j.Set("auth_str", that.Parser.Auth)
+	}
+	if that.Parser.OBFSParam != "" {
+		j.Set("obfs", that.Parser.OBFSParam)
+	}
+	j.Set("tag", utils.OutboundTag)
+	j = PrepareStreamString(j, that.Parser.StreamField)
+	// hysteria runs over QUIC, no v2ray transport is allowed.
+	j.Remove("transport")
+	return j.MustToJsonString()
+}
+
+func (that *SHysteriaOut) GetOutboundStr() string {
+	if that.Parser.Address == "" && that.Parser.Port == 0 {
+		return ""
+	}
+	if that.outbound == "" {
+		that.outbound = that.getSettings()
+	}
+	return that.outbound
+}
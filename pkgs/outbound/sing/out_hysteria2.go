@@ -0,0 +1,110 @@
+package sing
+
+import (
+	"github.com/ngwayzinmoe/uri-to-json/pkgs/parser"
+	"github.com/ngwayzinmoe/uri-to-json/pkgs/utils"
+	"github.com/gogf/gf/v2/encoding/gjson"
+)
+
+/*
+https://sing-box.sagernet.org/configuration/outbound/hysteria2/
+
+{
+  "type": "hysteria2",
+  "tag": "hy2-out",
+
+  "server": "127.0.0.1",
+  "server_port": 1080,
+  "up_mbps": 100,
+  "down_mbps": 100,
+  "obfs": {
+    "type": "salamander",
+    "password": "cry_me_a_r1ver"
+  },
+  "password": "goofy_ahh_password",
+  "network": "tcp",
+  "tls": {},
+  "brutal_debug": false,
+
+  ... // Dial Fields
+}
+*/
+
+var SingHysteria2 string = `{
+	"type": "hysteria2",
+	"tag": "hy2-out",
+	"server": "127.0.0.1",
+	"server_port": 1080,
+	"password": ""
+}`
+
+type SHysteria2Out struct {
+	RawUri   string
+	Parser   *parser.ParserHysteria2
+	outbound string
+}
+
+func (that *SHysteria2Out) Parse(rawUri string) error {
+	that.Parser = &parser.ParserHysteria2{}
+	return that.Parser.Parse(rawUri)
+}
+
+func (that *SHysteria2Out) Addr() string {
+	if that.Parser == nil {
+		return ""
+	}
+	return that.Parser.GetAddr()
+}
+
+func (that *SHysteria2Out) Port() int {
+	if that.Parser == nil {
+		return 0
+	}
+	return that.Parser.GetPort()
+}
+
+func (that *SHysteria2Out) Scheme() string {
+	return parser.SchemeHysteria2
+}
+
+func (that *SHysteria2Out) GetRawUri() string {
+	return that.RawUri
+}
+
+func (that *SHysteria2Out) getSettings() string {
+	j := gjson.New(SingHysteria2)
+	j.Set("server", that.Parser.Config.Server)
+	j.Set("server_port", that.Parser.Config.Port)
+	j.Set("password", that.Parser.Config.Auth)
+	if that.Parser.Config.UpMbps > 0 {
+		j.Set("up_mbps", that.Parser.Config.UpMbps)
+	}
+	if that.Parser.Config.DownMbps > 0 {
+		j.Set("down_mbps", that.Parser.Config.DownMbps)
+	}
+	if that.Parser.Config.OBFSPass != "" {
+		obfs := that.Parser.Config.OBFS
+		if obfs == "" {
+			obfs = "salamander"
+		}
+		j.Set("obfs", map[string]string{
+			"type":     obfs,
+			"password": that.Parser.Config.OBFSPass,
+		})
+	}
+	j.Set("tag", utils.OutboundTag)
+	j = PrepareStreamString(j, that.Parser.StreamField)
+	// hysteria2 runs over QUIC, no v2ray transport is allowed.
+	j.Remove("transport")
+	return j.MustToJsonString()
+}
+
+func (that *SHysteria2Out) GetOutboundStr() string {
+	if that.Parser.Config.Server == "" || that.Parser.Config.Port == 0 {
+		return ""
+	}
+	if that.outbound == "" {
+		that.outbound = that.getSettings()
+	}
+	return that.outbound
+}
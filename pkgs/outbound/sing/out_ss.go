@@ -0,0 +1,113 @@
+package sing
+
+import (
+	"fmt"
+
+	"github.com/ngwayzinmoe/uri-to-json/pkgs/parser"
+	"github.com/ngwayzinmoe/uri-to-json/pkgs/utils"
+	"github.com/gogf/gf/v2/encoding/gjson"
+)
+
+/*
+https://sing-box.sagernet.org/configuration/outbound/shadowsocks/
+
+{
+  "type": "shadowsocks",
+  "tag": "ss-out",
+
+  "server": "127.0.0.1",
+  "server_port": 1080,
+  "method": "2022-blake3-aes-128-gcm",
+  "password": "8JCsPssfgS8tiRwiMlhARg==",
+  "plugin": "",
+  "plugin_opts": "",
+  "network": "udp",
+  "udp_over_tcp": false | {},
+  "multiplex": {},
+
+  ... // Dial Fields
+}
+*/
+
+var SingSS string = `{
+	"type": "shadowsocks",
+	"tag": "ss-out",
+	"server": "127.0.0.1",
+	"server_port": 1080,
+	"method": "2022-blake3-aes-128-gcm",
+	"password": "8JCsPssfgS8tiRwiMlhARg=="
+}`
+
+type SShadowSocksOut struct {
+	RawUri   string
+	Parser   *parser.ParserSS
+	outbound string
+}
+
+func (that *SShadowSocksOut) Parse(rawUri string) error {
+	that.Parser = &parser.ParserSS{}
+	if err := that.Parser.Parse(rawUri); err != nil {
+		return err
+	}
+	switch that.Parser.Plugin {
+	case "", parser.SSPluginObfs, parser.SSPluginV2ray:
+		return nil
+	default:
+		return parser.NewParseError(parser.ErrUnsupportedTransport, parser.SchemeSS, "plugin", that.Parser.Plugin)
+	}
+}
+
+func (that *SShadowSocksOut) Addr() string {
+	if that.Parser == nil {
+		return ""
+	}
+	return that.Parser.GetAddr()
+}
+
+func (that *SShadowSocksOut) Port() int {
+	if that.Parser == nil {
+		return 0
+	}
+	return that.Parser.GetPort()
+}
+
+func (that *SShadowSocksOut) Scheme() string {
+	return parser.SchemeSS
+}
+
+func (that *SShadowSocksOut) GetRawUri() string {
+	return that.RawUri
+}
+
+func (that *SShadowSocksOut) getSettings() string {
+	j := gjson.New(SingSS)
+	j.Set("server", that.Parser.Address)
+	j.Set("server_port", that.Parser.Port)
+	j.Set("method", that.Parser.Method)
+	j.Set("password", that.Parser.Password)
+	if that.Parser.Plugin != "" {
+		j.Set("plugin", that.Parser.Plugin)
+		j.Set("plugin_opts", that.Parser.PluginOptsString())
+	}
+	j.Set("tag", utils.OutboundTag)
+	return j.MustToJsonString()
+}
+
+func (that *SShadowSocksOut) GetOutboundStr() string {
+	if that.Parser.Address == "" && that.Parser.Port == 0 {
+		return ""
+	}
+	if that.outbound == "" {
+		that.outbound = that.getSettings()
+	}
+	return that.outbound
+}
+
+func TestSS() {
+	rawUri := "ss://aes-256-gcm:bad5fba5-a7bc-4709-882b-e15edad16cef@ah-cmi-1000m.ikun666.club:18878#🇨🇳_CN_中国->🇸🇬_SG_新加坡"
+	sso := &SShadowSocksOut{}
+	sso.Parse(rawUri)
+	o := sso.GetOutboundStr()
+	j := gjson.New(o)
+	fmt.Println(j.MustToJsonIndentString())
+}
@@ -0,0 +1,113 @@
+package sing
+
+import (
+	"fmt"
+
+	"github.com/ngwayzinmoe/uri-to-json/pkgs/parser"
+	"github.com/ngwayzinmoe/uri-to-json/pkgs/utils"
+	"github.com/gogf/gf/v2/encoding/gjson"
+)
+
+/*
+https://sing-box.sagernet.org/configuration/outbound/shadowsocksr/
+
+{
+  "type": "shadowsocksr",
+  "tag": "ssr-out",
+
+  "server": "127.0.0.1",
+  "server_port": 1080,
+  "method": "aes-128-cfb",
+  "password": "8JCsPssfgS8tiRwiMlhARg==",
+  "obfs": "plain",
+  "obfs_param": "",
+  "protocol": "origin",
+  "protocol_param": "",
+  "network": "udp",
+
+  ... // Dial Fields
+}
+
+Only available when sing-box is built with tag "with_shadowsocksr".
+*/
+
+var SingSSR string = `{
+	"type": "shadowsocksr",
+	"tag": "ssr-out",
+	"server": "127.0.0.1",
+	"server_port": 1080,
+	"method": "aes-128-cfb",
+	"password": "8JCsPssfgS8tiRwiMlhARg==",
+	"obfs": "plain",
+	"obfs_param": "",
+	"protocol": "origin",
+	"protocol_param": ""
+}`
+
+type SShadowSocksROut struct {
+	RawUri   string
+	Parser   *parser.ParserSSR
+	outbound string
+}
+
+func (that *SShadowSocksROut) Parse(rawUri string) error {
+	that.Parser = &parser.ParserSSR{}
+	return that.Parser.Parse(rawUri)
+}
+
+func (that *SShadowSocksROut) Addr() string {
+	if that.Parser == nil {
+		return ""
+	}
+	return that.Parser.GetAddr()
+}
+
+func (that *SShadowSocksROut) Port() int {
+	if that.Parser == nil {
+		return 0
+	}
+	return that.Parser.GetPort()
+}
+
+func (that *SShadowSocksROut) Scheme() string {
+	return parser.SchemeSSR
+}
+
+func (that *SShadowSocksROut) GetRawUri() string {
+	return that.RawUri
+}
+
+func (that *SShadowSocksROut) getSettings() string {
+	j := gjson.New(SingSSR)
+	j.Set("server", that.Parser.Address)
+	j.Set("server_port", that.Parser.Port)
+	j.Set("method", that.Parser.Method)
+	j.Set("password", that.Parser.Password)
+	j.Set("obfs", that.Parser.OBFS)
+	j.Set("obfs_param", that.Parser.OBFSParam)
+	if that.Parser.Proto != "" {
+		j.Set("protocol", that.Parser.Proto)
+	}
+	j.Set("protocol_param", that.Parser.ProtoParam)
+	j.Set("tag", utils.OutboundTag)
+	return j.MustToJsonString()
+}
+
+func (that *SShadowSocksROut) GetOutboundStr() string {
+	if that.Parser.Address == "" && that.Parser.Port == 0 {
+		return ""
+	}
+	if that.outbound == "" {
+		that.outbound = that.getSettings()
+	}
+	return that.outbound
+}
+
+func TestSSR() {
+	rawUri := "ssr://94.23.116.190:443:origin:aes-256-ctr:tls1.2_ticket_auth:SG93ZHlCeXBhc3NlcjIwMjI=/?obfsparam=&protoparam=&remarks=5rOV5Zu9XzA4MjgwMDk&group="
+	so := &SShadowSocksROut{}
+	so.Parse(rawUri)
+	o := so.GetOutboundStr()
+	j := gjson.New(o)
+	fmt.Println(j.MustToJsonIndentString())
+}
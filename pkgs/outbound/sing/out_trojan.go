@@ -0,0 +1,101 @@
+package sing
+
+import (
+	"fmt"
+
+	"github.com/ngwayzinmoe/uri-to-json/pkgs/parser"
+	"github.com/ngwayzinmoe/uri-to-json/pkgs/utils"
+	"github.com/gogf/gf/v2/encoding/gjson"
+)
+
+/*
+https://sing-box.sagernet.org/configuration/outbound/trojan/
+
+{
+  "type": "trojan",
+  "tag": "trojan-out",
+
+  "server": "127.0.0.1",
+  "server_port": 1080,
+  "password": "8JCsPssfgS8tiRwiMlhARg==",
+  "network": "tcp",
+  "tls": {},
+  "multiplex": {},
+  "transport": {},
+
+  ... // Dial Fields
+}
+*/
+
+var SingTrojan string = `{
+	"type": "trojan",
+	"tag": "trojan-out",
+	"server": "127.0.0.1",
+	"server_port": 1080,
+	"password": "8JCsPssfgS8tiRwiMlhARg=="
+}`
+
+type STrojanOut struct {
+	RawUri   string
+	Parser   *parser.ParserTrojan
+	outbound string
+}
+
+func (that *STrojanOut) Parse(rawUri string) error {
+	that.Parser = &parser.ParserTrojan{}
+	if err := that.Parser.Parse(rawUri); err != nil {
+		return err
+	}
+	return CheckNetwork(parser.SchemeTrojan, that.Parser.StreamField)
+}
+
+func (that *STrojanOut) Addr() string {
+	if that.Parser == nil {
+		return ""
+	}
+	return that.Parser.GetAddr()
+}
+
+func (that *STrojanOut) Port() int {
+	if that.Parser == nil {
+		return 0
+	}
+	return that.Parser.GetPort()
+}
+
+func (that *STrojanOut) Scheme() string {
+	return parser.SchemeTrojan
+}
+
+func (that *STrojanOut) GetRawUri() string {
+	return that.RawUri
+}
+
+func (that *STrojanOut) getSettings() string {
+	j := gjson.New(SingTrojan)
+	j.Set("server", that.Parser.Address)
+	j.Set("server_port", that.Parser.Port)
+	j.Set("password", that.Parser.Password)
+	j.Set("tag", utils.OutboundTag)
+	j = PrepareStreamString(j, that.Parser.StreamField)
+	return j.MustToJsonString()
+}
+
+func (that *STrojanOut) GetOutboundStr() string {
+	if that.Parser.Address == "" && that.Parser.Port == 0 {
+		return ""
+	}
+	if that.outbound == "" {
+		that.outbound = that.getSettings()
+	}
+	return that.outbound
+}
+
+func TestTrojan() {
+	rawUri := "trojan://4d706727-996f-4427-930d-60f3bd414cf9@cnamemk.ciscocdn1.live:443?type=ws&sni=c2mk.ciscocdn1.live&allowInsecure=1&path=/rDCYQta83d0oPABKBhcIX#🇺🇸_US_美国->🇵🇱_PL_波兰"
+	to := &STrojanOut{}
+	to.Parse(rawUri)
+	o := to.GetOutboundStr()
+	j := gjson.New(o)
+	fmt.Println(j.MustToJsonIndentString())
+}
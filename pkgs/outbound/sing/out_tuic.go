@@ -0,0 +1,102 @@
+package sing
+
+import (
+	"github.com/ngwayzinmoe/uri-to-json/pkgs/parser"
+	"github.com/ngwayzinmoe/uri-to-json/pkgs/utils"
+	"github.com/gogf/gf/v2/encoding/gjson"
+)
+
+/*
+https://sing-box.sagernet.org/configuration/outbound/tuic/
+
+{
+  "type": "tuic",
+  "tag": "tuic-out",
+
+  "server": "127.0.0.1",
+  "server_port": 1080,
+  "uuid": "2DD61D93-75D8-4DA4-AC0E-6AECE7EAC365",
+  "password": "hello",
+  "congestion_control": "cubic",
+  "udp_relay_mode": "native",
+  "udp_over_stream": false,
+  "zero_rtt_handshake": false,
+  "heartbeat": "10s",
+  "network": "tcp",
+  "tls": {},
+
+  ... // Dial Fields
+}
+*/
+
+var SingTUIC string = `{
+	"type": "tuic",
+	"tag": "tuic-out",
+	"server": "127.0.0.1",
+	"server_port": 1080,
+	"uuid": "",
+	"password": "",
+	"congestion_control": "cubic",
+	"udp_relay_mode": "native"
+}`
+
+type STUICOut struct {
+	RawUri   string
+	Parser   *parser.ParserTUIC
+	outbound string
+}
+
+func (that *STUICOut) Parse(rawUri string) error {
+	that.Parser = &parser.ParserTUIC{}
+	return that.Parser.Parse(rawUri)
+}
+
+func (that *STUICOut) Addr() string {
+	if that.Parser == nil {
+		return ""
+	}
+	return that.Parser.GetAddr()
+}
+
+func (that *STUICOut) Port() int {
+	if that.Parser == nil {
+		return 0
+	}
+	return that.Parser.GetPort()
+}
+
+func (that *STUICOut) Scheme() string {
+	return parser.SchemeTUIC
+}
+
+func (that *STUICOut) GetRawUri() string {
+	return that.RawUri
+}
+
+func (that *STUICOut) getSettings() string {
+	j := gjson.New(SingTUIC)
+	j.Set("server", that.Parser.Address)
+	j.Set("server_port", that.Parser.Port)
+	j.Set("uuid", that.Parser.UUID)
+	j.Set("password", that.Parser.Password)
+	j.Set("congestion_control", that.Parser.CongestionControl)
+	j.Set("udp_relay_mode", that.Parser.UDPRelayMode)
+	j.Set("tag", utils.OutboundTag)
+	j = PrepareStreamString(j, that.Parser.StreamField)
+	// tuic runs over QUIC, no v2ray transport is allowed.
+	j.Remove("transport")
+	if that.Parser.DisableSNI {
+		j.Set("tls.disable_sni", true)
+	}
+	return j.MustToJsonString()
+}
+
+func (that *STUICOut) GetOutboundStr() string {
+	if that.Parser.Address == "" && that.Parser.Port == 0 {
+		return ""
+	}
+	if that.outbound == "" {
+		that.outbound = that.getSettings()
+	}
+	return that.outbound
+}
@@ -0,0 +1,112 @@
+package sing
+
+import (
+	"fmt"
+
+	"github.com/ngwayzinmoe/uri-to-json/pkgs/parser"
+	"github.com/ngwayzinmoe/uri-to-json/pkgs/utils"
+	"github.com/gogf/gf/v2/encoding/gjson"
+)
+
+/*
+https://sing-box.sagernet.org/configuration/outbound/vless/
+
+{
+  "type": "vless",
+  "tag": "vless-out",
+
+  "server": "127.0.0.1",
+  "server_port": 1080,
+  "uuid": "bf000d23-0752-40b4-affe-68f7707a9661",
+  "flow": "xtls-rprx-vision",
+  "network": "tcp",
+  "tls": {},
+  "packet_encoding": "",
+  "multiplex": {},
+  "transport": {},
+
+  ... // Dial Fields
+}
+
+Flow:
+xtls-rprx-vision is the only flow sing-box accepts.
+*/
+
+var SingVless string = `{
+	"type": "vless",
+	"tag": "vless-out",
+	"server": "127.0.0.1",
+	"server_port": 1080,
+	"uuid": "bf000d23-0752-40b4-affe-68f7707a9661"
+}`
+
+type SVlessOut struct {
+	RawUri   string
+	Parser   *parser.ParserVless
+	outbound string
+}
+
+func (that *SVlessOut) Parse(rawUri string) error {
+	that.Parser = &parser.ParserVless{}
+	if err := that.Parser.Parse(rawUri); err != nil {
+		return err
+	}
+	return CheckNetwork(parser.SchemeVless, that.Parser.StreamField)
+}
+
+func (that *SVlessOut) Addr() string {
+	if that.Parser == nil {
+		return ""
+	}
+	return that.Parser.GetAddr()
+}
+
+func (that *SVlessOut) Port() int {
+	if that.Parser == nil {
+		return 0
+	}
+	return that.Parser.GetPort()
+}
+
+func (that *SVlessOut) Scheme() string {
+	return parser.SchemeVless
+}
+
+func (that *SVlessOut) GetRawUri() string {
+	return that.RawUri
+}
+
+func (that *SVlessOut) getSettings() string {
+	j := gjson.New(SingVless)
+	j.Set("server", that.Parser.Address)
+	j.Set("server_port", that.Parser.Port)
+	j.Set("uuid", that.Parser.UUID)
+	if that.Parser.Flow == "xtls-rprx-vision" || that.Parser.Flow == "xtls-rprx-vision-udp443" {
+		j.Set("flow", "xtls-rprx-vision")
+	}
+	if that.Parser.PacketEncoding != "" {
+		j.Set("packet_encoding", that.Parser.PacketEncoding)
+	}
+	j.Set("tag", utils.OutboundTag)
+	j = PrepareStreamString(j, that.Parser.StreamField)
+	return j.MustToJsonString()
+}
+
+func (that *SVlessOut) GetOutboundStr() string {
+	if that.Parser.Address == "" && that.Parser.Port == 0 {
+		return ""
+	}
+	if that.outbound == "" {
+		that.outbound = that.getSettings()
+	}
+	return that.outbound
+}
+
+func TestVless() {
+	rawUri := "vless://d572752d-b079-4169-a1a1-3da5721a8ab4@m2rel.siasepid.sbs:80?encryption=none&security=reality&sni=tgju.org&fp=firefox&pbk=HgrpXJzQo2liQMY9YAPq1_PuiDXNNBLx8hRyVVfUZko&sid=af41f983&spx=/&type=grpc&serviceName=@V2rayNGmat&mode=multi#德国_0828096"
+	vo := &SVlessOut{}
+	vo.Parse(rawUri)
+	o := vo.GetOutboundStr()
+	j := gjson.New(o)
+	fmt.Println(j.MustToJsonIndentString())
+}
@@ -0,0 +1,120 @@
+package sing
+
+import (
+	"fmt"
+
+	"github.com/ngwayzinmoe/uri-to-json/pkgs/parser"
+	"github.com/ngwayzinmoe/uri-to-json/pkgs/utils"
+	"github.com/gogf/gf/v2/encoding/gjson"
+	"github.com/gogf/gf/v2/util/gconv"
+)
+
+/*
+https://sing-box.sagernet.org/configuration/outbound/vmess/
+
+{
+  "type": "vmess",
+  "tag": "vmess-out",
+
+  "server": "127.0.0.1",
+  "server_port": 1080,
+  "uuid": "bf000d23-0752-40b4-affe-68f7707a9661",
+  "security": "auto",
+  "alter_id": 0,
+  "global_padding": false,
+  "authenticated_length": true,
+  "network": "tcp",
+  "tls": {},
+  "packet_encoding": "",
+  "transport": {},
+  "multiplex": {},
+
+  ... // Dial Fields
+}
+*/
+
+var SingVmess string = `{
+	"type": "vmess",
+	"tag": "vmess-out",
+	"server": "127.0.0.1",
+	"server_port": 1080,
+	"uuid": "bf000d23-0752-40b4-affe-68f7707a9661",
+	"security": "auto",
+	"alter_id": 0
+}`
+
+type SVmessOut struct {
+	RawUri   string
+	Parser   *parser.ParserVmess
+	outbound string
+}
+
+func (that *SVmessOut) Parse(rawUri string) error {
+	that.Parser = &parser.ParserVmess{}
+	if err := that.Parser.Parse(rawUri); err != nil {
+		return err
+	}
+	return CheckNetwork(parser.SchemeVmess, that.Parser.StreamField)
+}
+
+func (that *SVmessOut) Addr() string {
+	if that.Parser == nil {
+		return ""
+	}
+	return that.Parser.GetAddr()
+}
+
+func (that *SVmessOut) Port() int {
+	if that.Parser == nil {
+		return 0
+	}
+	return that.Parser.GetPort()
+}
+
+func (that *SVmessOut) Scheme() string {
+	return parser.SchemeVmess
+}
+
+func (that *SVmessOut) GetRawUri() string {
+	return that.RawUri
+}
+
+func (that *SVmessOut) getSettings() string {
+	if that.Parser.Address == "" || that.Parser.Port == 0 {
+		return "{}"
+	}
+	j := gjson.New(SingVmess)
+	j.Set("server", that.Parser.Address)
+	j.Set("server_port", that.Parser.Port)
+	j.Set("uuid", that.Parser.UUID)
+	j.Set("alter_id", gconv.Int(that.Parser.AID))
+	if that.Parser.Security == "" {
+		that.Parser.Security = "auto"
+	}
+	j.Set("security", that.Parser.Security)
+	if that.Parser.PacketEncoding != "" {
+		j.Set("packet_encoding", that.Parser.PacketEncoding)
+	}
+	j.Set("tag", utils.OutboundTag)
+	j = PrepareStreamString(j, that.Parser.StreamField)
+	return j.MustToJsonString()
+}
+
+func (that *SVmessOut) GetOutboundStr() string {
+	if that.Parser.Address == "" && that.Parser.Port == 0 {
+		return ""
+	}
+	if that.outbound == "" {
+		that.outbound = that.getSettings()
+	}
+	return that.outbound
+}
+
+func TestVmess() {
+	rawUri := "vmess://{\"add\":\"bobbykotick.rip\",\"host\":\"Kansas.bobbykotick.rip\",\"sni\":\"Kansas.bobbykotick.rip\",\"id\":\"D213ED80-199B-4A01-9D62-BBCBA9C16226\",\"net\":\"ws\",\"path\":\"\\/speedtest\",\"port\":\"443\",\"ps\":\"GetAFreeNode.com-Kansas\",\"tls\":\"tls\",\"fp\":\"android\",\"alpn\":\"h2,http\\/1.1\",\"v\":2,\"aid\":0,\"type\":\"none\"}"
+	vo := &SVmessOut{}
+	vo.Parse(rawUri)
+	o := vo.GetOutboundStr()
+	j := gjson.New(o)
+	fmt.Println(j.MustToJsonIndentString())
+}
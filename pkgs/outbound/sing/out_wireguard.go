@@ -0,0 +1,133 @@
+package sing
+
+import (
+	"github.com/ngwayzinmoe/uri-to-json/pkgs/parser"
+	"github.com/ngwayzinmoe/uri-to-json/pkgs/utils"
+	"github.com/gogf/gf/v2/encoding/gjson"
+)
+
+/*
+https://sing-box.sagernet.org/configuration/outbound/wireguard/
+
+{
+  "type": "wireguard",
+  "tag": "wireguard-out",
+
+  "server": "127.0.0.1",
+  "server_port": 1080,
+  "system_interface": false,
+  "gso": false,
+  "interface_name": "wg0",
+  "local_address": [
+    "10.0.0.2/32"
+  ],
+  "private_key": "YNXtAzepDqRv9H52osJVDQnznT5AL11eCK3ESpwSt04=",
+  "peers": [],
+  "peer_public_key": "Z1XXLsKYkYxuiYjJIkRvtIKFepCYHTgON+GwPq7SOV4=",
+  "pre_shared_key": "31aIhAPwktDGpH4JDhA8GNvjFXEf/a6+UaQRyOAiyfM=",
+  "reserved": [0, 0, 0],
+  "workers": 4,
+  "mtu": 1408,
+  "network": "tcp",
+
+  ... // Dial Fields
+}
+*/
+
+var SingWireguard string = `{
+	"type": "wireguard",
+	"tag": "wireguard-out",
+	"server": "127.0.0.1",
+	"server_port": 1080,
+	"local_address": [],
+	"private_key": "",
+	"peer_public_key": "",
+	"mtu": 1280
+}`
+
+type SWireguardOut struct {
+	RawUri   string
+	Parser   *parser.ParserWirguard
+	outbound string
+}
+
+func (that *SWireguardOut) Parse(rawUri string) error {
+	that.Parser = &parser.ParserWirguard{}
+	return that.Parser.Parse(rawUri)
+}
+
+func (that *SWireguardOut) Addr() string {
+	if that.Parser == nil {
+		return ""
+	}
+	return that.Parser.GetAddr()
+}
+
+func (that *SWireguardOut) Port() int {
+	if that.Parser == nil {
+		return 0
+	}
+	return that.Parser.GetPort()
+}
+
+func (that *SWireguardOut) Scheme() string {
+	return parser.SchemeWireguard
+}
+
+func (that *SWireguardOut) GetRawUri() string {
+	return that.RawUri
+}
+
+func (that *SWireguardOut) getSettings() string {
+	j := gjson.New(SingWireguard)
+	j.Set("local_address", that.Parser.LocalAddresses())
+	j.Set("private_key", that.Parser.PrivateKey)
+	if len(that.Parser.Reserved) > 0 {
+		j.Set("reserved", that.Parser.Reserved)
+	}
+	if len(that.Parser.Peers) > 1 {
+		// multi-peer mode, server and peer_public_key are replaced by peers.
+		j.Remove("server")
+		j.Remove("server_port")
+		j.Remove("peer_public_key")
+		peers := []map[string]interface{}{}
+		for _, p := range that.Parser.Peers {
+			peer := map[string]interface{}{
+				"server":      p.Address,
+				"server_port": p.Port,
+				"public_key":  p.PublicKey,
+				"allowed_ips": []string{"0.0.0.0/0", "::/0"},
+			}
+			if len(p.AllowedIPs) > 0 {
+				peer["allowed_ips"] = p.AllowedIPs
+			}
+			if p.PresharedKey != "" {
+				peer["pre_shared_key"] = p.PresharedKey
+			}
+			peers = append(peers, peer)
+		}
+		j.Set("peers", peers)
+	} else {
+		j.Set("server", that.Parser.Address)
+		j.Set("server_port", that.Parser.Port)
+		j.Set("peer_public_key", that.Parser.PublicKey)
+		if len(that.Parser.Peers) > 0 && that.Parser.Peers[0].PresharedKey != "" {
+			j.Set("pre_shared_key", that.Parser.Peers[0].PresharedKey)
+		}
+	}
+	if that.Parser.MTU > 0 {
+		j.Set("mtu", that.Parser.MTU)
+	}
+	j.Set("tag", utils.OutboundTag)
+	return j.MustToJsonString()
+}
+
+func (that *SWireguardOut) GetOutboundStr() string {
+	if that.Parser.Address == "" && that.Parser.Port == 0 {
+		return ""
+	}
+	if that.outbound == "" {
+		that.outbound = that.getSettings()
+	}
+	return that.outbound
+}
@@ -0,0 +1,69 @@
+package sing
+
+import (
+	"testing"
+
+	"github.com/gogf/gf/v2/encoding/gjson"
+)
+
+const wgConf = `wireguard://[Interface]
+PrivateKey = cHJpdmF0ZS1rZXktcHJpdmF0ZS1rZXktcHJpdmF0ZS1rZQ==
+Address = 10.0.0.2/32
+Reserved = 1,2,3
+
+[Peer]
+PublicKey = cHVibGljLWtleS1wdWJsaWMta2V5LXB1YmxpYy1rZXk=
+Endpoint = 1.2.3.4:51820
+PersistentKeepalive = 25
+
+[Peer]
+PublicKey = cHVibGljLWtleS1wdWJsaWMta2V5LXB1YmxpYy1rZXk=
+Endpoint = 5.6.7.8:51820
+`
+
+func TestWireguardPeers(t *testing.T) {
+	const (
+		key  = "cHJpdmF0ZS1rZXktcHJpdmF0ZS1rZXktcHJpdmF0ZS1rZQ%3D%3D"
+		pub  = "cHVibGljLWtleS1wdWJsaWMta2V5LXB1YmxpYy1rZXk%3D"
+		base = "wireguard://" + key + "@1.2.3.4:51820?publickey=" + pub + "&address=10.0.0.2%2F32&reserved=1,2,3"
+	)
+	for _, c := range []struct {
+		rawUri string
+		peers  int
+	}{
+		{base, 0},
+		// the legacy outbound has no keepalive field, it is dropped.
+		{base + "&keepalive=25", 0},
+		{wgConf, 2},
+	} {
+		out := &SWireguardOut{}
+		if err := out.Parse(c.rawUri); err != nil {
+			t.Fatalf("%s: %s", c.rawUri, err)
+		}
+		j := gjson.New(out.GetOutboundStr())
+		if got := j.Get("reserved").Ints(); len(got) != 3 || got[2] != 3 {
+			t.Errorf("%s: reserved = %v", c.rawUri, got)
+		}
+		peers := j.GetJsons("peers")
+		if len(peers) != c.peers {
+			t.Fatalf("%s: %d peers, want %d", c.rawUri, len(peers), c.peers)
+		}
+		if c.peers == 0 {
+			if j.Contains("persistent_keepalive_interval") {
+				t.Errorf("%s: keepalive is set", c.rawUri)
+			}
+			if j.Get("server").String() != "1.2.3.4" || j.Get("peer_public_key").String() == "" {
+				t.Errorf("%s: missing server or peer_public_key: %s", c.rawUri, j.MustToJsonString())
+			}
+			continue
+		}
+		if j.Contains("server") || j.Contains("peer_public_key") {
+			t.Errorf("%s: server is set next to peers", c.rawUri)
+		}
+		for _, peer := range peers {
+			if peer.Contains("reserved") || peer.Contains("persistent_keepalive_interval") {
+				t.Errorf("%s: reserved or keepalive set on a peer: %s", c.rawUri, peer.MustToJsonString())
+			}
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package sing
+
+import (
+	"strings"
+
+	"github.com/ngwayzinmoe/uri-to-json/pkgs/parser"
+	"github.com/ngwayzinmoe/uri-to-json/pkgs/utils"
+	"github.com/gogf/gf/v2/encoding/gjson"
+	"github.com/gogf/gf/v2/util/gconv"
+)
+
+/*
+https://sing-box.sagernet.org/configuration/shared/tls/#outbound
+https://sing-box.sagernet.org/configuration/shared/v2ray-transport/
+
+Supports:
+- TCP(none/http) / WS / gRPC / HTTPUpgrade / QUIC / H2
+- TLS / uTLS / Reality
+*/
+
+var SingTLS = `{
+	"enabled": true,
+	"server_name": "",
+	"insecure": false
+}`
+
+var SingUTLS = `{
+	"enabled": true,
+	"fingerprint": "chrome"
+}`
+
+var SingReality = `{
+	"enabled": true,
+	"public_key": "",
+	"short_id": ""
+}`
+
+var SingTransportHTTP = `{
+	"type": "http",
+	"host": [],
+	"path": "/"
+}`
+
+var SingTransportWebSocket = `{
+	"type": "ws",
+	"path": "/",
+	"headers": {}
+}`
+
+var SingTransportGRPC = `{
+	"type": "grpc",
+	"service_name": ""
+}`
+
+var SingTransportHTTPUpgrade = `{
+	"type": "httpupgrade",
+	"host": "",
+	"path": "/"
+}`
+
+var SingTransportQUIC = `{
+	"type": "quic"
+}`
+
+// Networks sing-box has no transport for.
+var unsupportedNetworks = map[string]struct{}{
+	"kcp":   {},
+	"xhttp": {},
+}
+
+// CheckNetwork reports transports that sing-box can't emit.
+func CheckNetwork(scheme string, sf *parser.StreamField) error {
+	if _, ok := unsupportedNetworks[sf.Network]; ok {
+		return parser.NewParseError(parser.ErrUnsupportedTransport, scheme, "type", sf.Network)
+	}
+	return nil
+}
+
+// PrepareStreamString fills the "tls" and "transport" objects of a sing-box outbound.
+func PrepareStreamString(cnf *gjson.Json, sf *parser.StreamField) *gjson.Json {
+	if cnf == nil || sf == nil {
+		return cnf
+	}
+
+	// ---------------- Transport ----------------
+	transport := "{}"
+	switch sf.Network {
+	case "tcp", "":
+		if sf.TCPHeaderType == "http" {
+			j := gjson.New(SingTransportHTTP)
+			if sf.Host != "" {
+				j.Set("host", strings.Split(sf.Host, ","))
+			}
+			if sf.Path != "" {
+				j.Set("path", sf.Path)
+			}
+			transport = j.MustToJsonString()
+		}
+	case "ws":
+		j := gjson.New(SingTransportWebSocket)
+		if sf.Path != "" {
+			j.Set("path", sf.Path)
+		}
+		if sf.Host != "" {
+			j.Set("headers.Host", sf.Host)
+		}
+		transport = j.MustToJsonString()
+	case "grpc":
+		j := gjson.New(SingTransportGRPC)
+		j.Set("service_name", sf.GRPCServiceName)
+		transport = j.MustToJsonString()
+	case "httpupgrade":
+		j := gjson.New(SingTransportHTTPUpgrade)
+		if sf.Path != "" {
+			j.Set("path", sf.Path)
+		}
+		j.Set("host", sf.Host)
+		transport = j.MustToJsonString()
+	case "quic":
+		transport = SingTransportQUIC
+	case "http":
+		j := gjson.New(SingTransportHTTP)
+		if sf.Host != "" {
+			j.Set("host", strings.Split(sf.Host, ","))
+		}
+		if sf.Path != "" {
+			j.Set("path", sf.Path)
+		}
+		transport = j.MustToJsonString()
+	}
+	cnf = utils.SetJsonObjectByString("transport", transport, cnf)
+
+	// ---------------- Security ----------------
+	if sf.StreamSecurity != "tls" && sf.StreamSecurity != "reality" {
+		return utils.SetJsonObjectByString("tls", `{"enabled": false}`, cnf)
+	}
+	j := gjson.New(SingTLS)
+	sn := sf.ServerName
+	if sn == "" {
+		sn = sf.Host
+	}
+	j.Set("server_name", sn)
+	j.Set("insecure", gconv.Bool(sf.TLSAllowInsecure))
+	if sf.TLSALPN != "" {
+		j.Set("alpn", strings.Split(sf.TLSALPN, ","))
+	}
+	if sf.Fingerprint != "" || sf.StreamSecurity == "reality" {
+		u := gjson.New(SingUTLS)
+		if sf.Fingerprint != "" {
+			u.Set("fingerprint", sf.Fingerprint)
+		}
+		j = utils.SetJsonObjectByString("utls", u.MustToJsonString(), j)
+	}
+	if sf.StreamSecurity == "reality" {
+		r := gjson.New(SingReality)
+		r.Set("public_key", sf.RealityPublicKey)
+		r.Set("short_id", sf.RealityShortId)
+		j = utils.SetJsonObjectByString("reality", r.MustToJsonString(), j)
+	}
+	return utils.SetJsonObjectByString("tls", j.MustToJsonString(), cnf)
+}
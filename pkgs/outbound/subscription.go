@@ -0,0 +1,88 @@
+package outbound
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ngwayzinmoe/uri-to-json/pkgs/parser"
+	"github.com/ngwayzinmoe/uri-to-json/pkgs/utils"
+)
+
+var SupportedSchemes = []string{
+	parser.SchemeVmess,
+	parser.SchemeVless,
+	parser.SchemeTrojan,
+	parser.SchemeSS,
+	parser.SchemeSSR,
+	parser.SchemeHysteria,
+	parser.SchemeHysteria2,
+	parser.SchemeTUIC,
+	parser.SchemeWireguard,
+}
+
+func isSupportedScheme(scheme string) bool {
+	for _, s := range SupportedSchemes {
+		if s == scheme {
+			return true
+		}
+	}
+	return false
+}
+
+// DecodeSubscriptionBody returns the newline separated uris of a subscription payload,
+// which is either plain text or base64 (standard/URL-safe, padded or not).
+func DecodeSubscriptionBody(content string) (uris []string, err error) {
+	content = strings.TrimSpace(strings.TrimPrefix(content, "\ufeff"))
+	if content == "" {
+		return nil, fmt.Errorf("empty subscription")
+	}
+	if !strings.Contains(content, "://") {
+		decoded, ok := decodeSubscriptionBase64(content)
+		if !ok {
+			return nil, fmt.Errorf("subscription is neither plain text nor base64")
+		}
+		content = decoded
+	}
+	for _, line := range strings.Split(strings.ReplaceAll(content, "\r", "\n"), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		uris = append(uris, line)
+	}
+	return
+}
+
+func decodeSubscriptionBase64(content string) (string, bool) {
+	// base64 bodies are often wrapped every 76 characters.
+	content = strings.Join(strings.Fields(content), "")
+	content = strings.ReplaceAll(content, "-", "+")
+	content = strings.ReplaceAll(content, "_", "/")
+	content = strings.TrimRight(content, "=")
+	decoded, err := base64.RawStdEncoding.DecodeString(content)
+	if err != nil || !strings.Contains(string(decoded), "://") {
+		return "", false
+	}
+	return string(decoded), true
+}
+
+// DecodeSubscription parses a subscription payload into a Result, one ProxyItem per supported uri.
+// Items are converted for clientType, or for a capable client of each uri when it is omitted.
+func DecodeSubscription(content string, clientType ...ClientType) (*Result, error) {
+	uris, err := DecodeSubscriptionBody(content)
+	if err != nil {
+		return nil, err
+	}
+	result := NewResult()
+	for _, rawUri := range uris {
+		if !isSupportedScheme(utils.ParseScheme(rawUri)) {
+			continue
+		}
+		// the parsers decode legacy base64 links themselves, the rest must stay escaped.
+		result.AddItem(ParseRawUriToProxyItem(rawUri, clientType...))
+	}
+	result.UpdateAt = time.Now().Format("2006-01-02 15:04:05")
+	return result, nil
+}
@@ -0,0 +1,25 @@
+package outbound
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+func TestDecodeSubscriptionKeepsEscapes(t *testing.T) {
+	uris := []string{
+		"vless://uuid@example.com:443?type=ws&security=tls&path=%2Fa%2Bb#plus",
+		"ss://" + base64.StdEncoding.EncodeToString([]byte("aes-256-gcm:pa/ss+wd")) + "@1.2.3.4:8388#sip002",
+	}
+	content := base64.StdEncoding.EncodeToString([]byte(strings.Join(uris, "\n")))
+	result, err := DecodeSubscription(content, XrayCore)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Vless) != 1 || !strings.Contains(result.Vless[0].Outbound, `"path":"/a+b"`) {
+		t.Errorf("vless path was altered: %+v", result.Vless)
+	}
+	if len(result.ShadowSocks) != 1 || !strings.Contains(result.ShadowSocks[0].Outbound, `"password":"pa/ss+wd"`) {
+		t.Errorf("ss password was altered: %+v", result.ShadowSocks)
+	}
+}
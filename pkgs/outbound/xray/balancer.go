@@ -0,0 +1,101 @@
+package xray
+
+import (
+	"fmt"
+
+	"github.com/gogf/gf/v2/encoding/gjson"
+)
+
+/*
+https://xtls.github.io/config/routing.html#balancerobject
+https://xtls.github.io/config/observatory.html
+
+Every outbound gets a unique tag starting with SelectorPrefix,
+they are grouped by one balancer and probed by observatory (or burstObservatory),
+so xray picks the fastest node automatically.
+*/
+
+var XrayBalancer string = `{
+	"tag": "balancer",
+	"selector": [],
+	"strategy": {
+		"type": "leastPing"
+	}
+}`
+
+var XrayObservatory string = `{
+	"subjectSelector": [],
+	"probeURL": "https://www.gstatic.com/generate_204",
+	"probeInterval": "1m",
+	"enableConcurrency": true
+}`
+
+var XrayBurstObservatory string = `{
+	"subjectSelector": [],
+	"pingConfig": {
+		"destination": "https://www.gstatic.com/generate_204",
+		"connectivity": "",
+		"interval": "1m",
+		"sampling": 3,
+		"timeout": "5s"
+	}
+}`
+
+type BalancerOptions struct {
+	Tag            string
+	SelectorPrefix string
+	Strategy       string // random | roundRobin | leastPing | leastLoad
+	ProbeURL       string
+	ProbeInterval  string
+	Burst          bool // use burstObservatory instead of observatory
+}
+
+func NewBalancerOptions() *BalancerOptions {
+	return &BalancerOptions{
+		Tag:            "balancer",
+		SelectorPrefix: "proxy-",
+		Strategy:       "leastPing",
+		ProbeURL:       "https://www.gstatic.com/generate_204",
+		ProbeInterval:  "1m",
+	}
+}
+
+// GetBalancerConfig wraps outbounds into a runnable xray config that balances between all of them.
+func GetBalancerConfig(outbounds []string, opts *ConfigOptions, bOpts *BalancerOptions) string {
+	if bOpts == nil {
+		bOpts = NewBalancerOptions()
+	}
+	j := newConfig(opts)
+	setOutbounds(j, outbounds, func(i int) string {
+		return fmt.Sprintf("%s%d", bOpts.SelectorPrefix, i)
+	})
+
+	b := gjson.New(XrayBalancer)
+	b.Set("tag", bOpts.Tag)
+	b.Set("selector", []string{bOpts.SelectorPrefix})
+	b.Set("strategy.type", bOpts.Strategy)
+	j.Set("routing.balancers", []interface{}{b.Map()})
+
+	rules := gjson.New(XrayRoutingRules)
+	rules.Append(".", map[string]interface{}{
+		"type":        "field",
+		"network":     "tcp,udp",
+		"balancerTag": bOpts.Tag,
+	})
+	j.Set("routing.rules", rules.Array())
+
+	if bOpts.Burst {
+		o := gjson.New(XrayBurstObservatory)
+		o.Set("subjectSelector", []string{bOpts.SelectorPrefix})
+		o.Set("pingConfig.destination", bOpts.ProbeURL)
+		o.Set("pingConfig.interval", bOpts.ProbeInterval)
+		j.Set("burstObservatory", o.Map())
+	} else {
+		o := gjson.New(XrayObservatory)
+		o.Set("subjectSelector", []string{bOpts.SelectorPrefix})
+		o.Set("probeURL", bOpts.ProbeURL)
+		o.Set("probeInterval", bOpts.ProbeInterval)
+		j.Set("observatory", o.Map())
+	}
+	return j.MustToJsonIndentString()
+}
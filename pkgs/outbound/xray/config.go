@@ -0,0 +1,163 @@
+package xray
+
+import (
+	"fmt"
+
+	"github.com/ngwayzinmoe/uri-to-json/pkgs/utils"
+	"github.com/gogf/gf/v2/encoding/gjson"
+)
+
+/*
+https://xtls.github.io/config/
+
+A complete client config which can be passed to "xray run -c":
+socks/http inbounds, proxy outbounds, direct/block outbounds, dns and routing.
+*/
+
+var XrayConfig string = `{
+	"log": {
+		"loglevel": "warning"
+	},
+	"dns": {
+		"servers": []
+	},
+	"inbounds": [
+		{
+			"tag": "socks-in",
+			"listen": "127.0.0.1",
+			"port": 10808,
+			"protocol": "socks",
+			"settings": {
+				"auth": "noauth",
+				"udp": true
+			},
+			"sniffing": {
+				"enabled": true,
+				"destOverride": ["http", "tls"]
+			}
+		},
+		{
+			"tag": "http-in",
+			"listen": "127.0.0.1",
+			"port": 10809,
+			"protocol": "http",
+			"settings": {},
+			"sniffing": {
+				"enabled": true,
+				"destOverride": ["http", "tls"]
+			}
+		}
+	],
+	"outbounds": [],
+	"routing": {
+		"domainStrategy": "IPIfNonMatch",
+		"rules": []
+	}
+}`
+
+var XrayDirectOut string = `{
+	"tag": "direct",
+	"protocol": "freedom",
+	"settings": {}
+}`
+
+var XrayBlockOut string = `{
+	"tag": "block",
+	"protocol": "blackhole",
+	"settings": {
+		"response": {
+			"type": "http"
+		}
+	}
+}`
+
+var XrayRoutingRules string = `[
+	{
+		"type": "field",
+		"ip": ["geoip:private"],
+		"outboundTag": "direct"
+	},
+	{
+		"type": "field",
+		"protocol": ["bittorrent"],
+		"outboundTag": "direct"
+	}
+]`
+
+type ConfigOptions struct {
+	Listen    string
+	SocksPort int
+	HttpPort  int
+	DNS       []string
+	LogLevel  string
+}
+
+func NewConfigOptions() *ConfigOptions {
+	return &ConfigOptions{
+		Listen:    "127.0.0.1",
+		SocksPort: 10808,
+		HttpPort:  10809,
+		DNS:       []string{"1.1.1.1", "8.8.8.8", "localhost"},
+		LogLevel:  "warning",
+	}
+}
+
+// newConfig fills the log, dns and inbounds of XrayConfig from opts.
+func newConfig(opts *ConfigOptions) *gjson.Json {
+	if opts == nil {
+		opts = NewConfigOptions()
+	}
+	j := gjson.New(XrayConfig)
+	j.Set("log.loglevel", opts.LogLevel)
+	j.Set("dns.servers", opts.DNS)
+
+	inbounds := []interface{}{}
+	for i, in := range j.GetJsons("inbounds") {
+		port := opts.SocksPort
+		if i == 1 {
+			port = opts.HttpPort
+		}
+		if port <= 0 {
+			continue
+		}
+		in.Set("listen", opts.Listen)
+		in.Set("port", port)
+		inbounds = append(inbounds, in.Map())
+	}
+	j.Set("inbounds", inbounds)
+	return j
+}
+
+// setOutbounds tags outbounds with tagOf(index) and appends the direct/block outbounds.
+func setOutbounds(j *gjson.Json, outbounds []string, tagOf func(int) string) {
+	obList := []interface{}{}
+	for _, oStr := range outbounds {
+		ob := gjson.New(oStr)
+		if ob == nil || ob.IsNil() {
+			continue
+		}
+		ob.Set("tag", tagOf(len(obList)))
+		obList = append(obList, ob.Map())
+	}
+	obList = append(obList, gjson.New(XrayDirectOut).Map(), gjson.New(XrayBlockOut).Map())
+	j.Set("outbounds", obList)
+}
+
+/*
+GetFullConfig wraps outbounds into a runnable xray config.
+
+The first outbound keeps utils.OutboundTag and receives all traffic,
+the others are tagged "proxy-1", "proxy-2", ... so that tags stay unique.
+A port set to 0 disables that inbound.
+*/
+func GetFullConfig(outbounds []string, opts *ConfigOptions) string {
+	j := newConfig(opts)
+	setOutbounds(j, outbounds, func(i int) string {
+		if i == 0 {
+			return utils.OutboundTag
+		}
+		return fmt.Sprintf("%s-%d", utils.OutboundTag, i)
+	})
+	j.Set("routing.rules", gjson.New(XrayRoutingRules).Array())
+	return j.MustToJsonIndentString()
+}
@@ -0,0 +1,303 @@
+package xray
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/ngwayzinmoe/uri-to-json/pkgs/parser"
+	"github.com/ngwayzinmoe/uri-to-json/pkgs/utils"
+	"github.com/gogf/gf/v2/encoding/gjson"
+	"github.com/gogf/gf/v2/util/gconv"
+)
+
+/*
+Reverse conversion: xray outbound object -> share link.
+
+Accepts the same shape produced by VmessOut/VlessOut/TrojanOut/ShadowSocksOut/Hysteria2Out,
+a list of such outbounds, or a full config with an "outbounds" list.
+*/
+
+// GetUrisFromConfig converts every supported outbound found in content to a share link.
+func GetUrisFromConfig(content string) (uris []string, err error) {
+	j := gjson.New(strings.TrimSpace(content))
+	if j == nil || j.IsNil() {
+		return nil, fmt.Errorf("invalid xray outbound json")
+	}
+	var outbounds []*gjson.Json
+	switch {
+	case j.Contains("outbounds"):
+		outbounds = j.GetJsons("outbounds")
+	case j.Contains("protocol"):
+		outbounds = []*gjson.Json{j}
+	default:
+		outbounds = j.GetJsons(".")
+	}
+	for _, ob := range outbounds {
+		switch ob.Get("protocol").String() {
+		case "freedom", "blackhole", "dns", "":
+			continue
+		}
+		rawUri, e := GetUriFromOutbound(ob.MustToJsonString())
+		if e != nil {
+			return uris, e
+		}
+		uris = append(uris, rawUri)
+	}
+	if len(uris) == 0 {
+		err = fmt.Errorf("no proxy outbound found")
+	}
+	return
+}
+
+// GetUriFromOutbound reconstructs a canonical share link from a single xray outbound object.
+func GetUriFromOutbound(outStr string) (string, error) {
+	j := gjson.New(outStr)
+	if j == nil || j.IsNil() {
+		return "", fmt.Errorf("invalid xray outbound json")
+	}
+	tag := j.Get("tag").String()
+	if tag == utils.OutboundTag {
+		tag = ""
+	}
+	stream := j.GetJson("streamSettings")
+	if stream == nil {
+		stream = gjson.New("{}")
+	}
+
+	switch protocol := j.Get("protocol").String(); protocol {
+	case "vmess":
+		return encodeVmess(j, stream, tag)
+	case "vless":
+		u := j.GetJson("settings.vnext.0")
+		if u == nil {
+			return "", fmt.Errorf("vless outbound has no vnext")
+		}
+		query := streamToQuery(stream)
+		encryption := u.Get("users.0.encryption").String()
+		if encryption == "" {
+			encryption = "none"
+		}
+		query.Set("encryption", encryption)
+		if flow := u.Get("users.0.flow").String(); flow != "" {
+			query.Set("flow", flow)
+		}
+		return buildUri(parser.SchemeVless, url.User(u.Get("users.0.id").String()), u.Get("address").String(), u.Get("port").Int(), query, tag), nil
+	case "trojan":
+		s := j.GetJson("settings.servers.0")
+		if s == nil {
+			return "", fmt.Errorf("trojan outbound has no servers")
+		}
+		query := streamToQuery(stream)
+		return buildUri(parser.SchemeTrojan, url.User(s.Get("password").String()), s.Get("address").String(), s.Get("port").Int(), query, tag), nil
+	case "shadowsocks":
+		s := j.GetJson("settings.servers.0")
+		if s == nil {
+			return "", fmt.Errorf("shadowsocks outbound has no servers")
+		}
+		method, password := s.Get("method").String(), s.Get("password").String()
+		userInfo := url.User(base64.RawURLEncoding.EncodeToString([]byte(method + ":" + password)))
+		if _, ok := parser.SS2022KeyLen[method]; ok {
+			// SIP002: 2022 keys are percent encoded instead of base64.
+			userInfo = url.UserPassword(method, password)
+		}
+		return buildUri(parser.SchemeSS, userInfo, s.Get("address").String(), s.Get("port").Int(), url.Values{}, tag), nil
+	case "hysteria2":
+		s := j.GetJson("settings")
+		if s == nil {
+			return "", fmt.Errorf("hysteria2 outbound has no settings")
+		}
+		query := url.Values{}
+		if sn := stream.Get("tlsSettings.serverName").String(); sn != "" {
+			query.Set("sni", sn)
+		}
+		if gconv.Bool(stream.Get("tlsSettings.allowInsecure").String()) {
+			query.Set("insecure", "1")
+		}
+		if pw := s.Get("password").String(); pw != "" {
+			query.Set("obfs", "salamander")
+			query.Set("obfs-password", pw)
+		}
+		return buildUri(parser.SchemeHysteria2, url.User(s.Get("auth").String()), s.Get("server").String(), s.Get("port").Int(), query, tag), nil
+	default:
+		return "", fmt.Errorf("unsupported xray protocol: %s", protocol)
+	}
+}
+
+func buildUri(scheme string, user *url.Userinfo, host string, port int, query url.Values, tag string) string {
+	u := &url.URL{
+		Scheme:   strings.TrimSuffix(scheme, "://"),
+		User:     user,
+		Host:     net.JoinHostPort(host, strconv.Itoa(port)),
+		RawQuery: query.Encode(),
+		Fragment: tag,
+	}
+	return u.String()
+}
+
+// streamToQuery translates streamSettings into the query keys used by vless/trojan links.
+func streamToQuery(stream *gjson.Json) url.Values {
+	query := url.Values{}
+	network := stream.Get("network").String()
+	if network == "" {
+		network = "tcp"
+	}
+	query.Set("type", network)
+
+	switch network {
+	case "tcp":
+		if stream.Get("tcpSettings.header.type").String() == "http" {
+			query.Set("headerType", "http")
+			if p := stream.Get("tcpSettings.header.request.path.0").String(); p != "" {
+				query.Set("path", p)
+			}
+			if h := stream.Get("tcpSettings.header.request.headers.Host.0").String(); h != "" {
+				query.Set("host", h)
+			}
+		}
+	case "ws":
+		if p := stream.Get("wsSettings.path").String(); p != "" {
+			query.Set("path", p)
+		}
+		if h := stream.Get("wsSettings.headers.Host").String(); h != "" {
+			query.Set("host", h)
+		}
+	case "grpc":
+		if sn := stream.Get("grpcSettings.serviceName").String(); sn != "" {
+			query.Set("serviceName", sn)
+		}
+		if stream.Get("grpcSettings.multiMode").Bool() {
+			query.Set("mode", "multi")
+		} else {
+			query.Set("mode", "gun")
+		}
+	case "httpupgrade":
+		if p := stream.Get("httpupgradeSettings.path").String(); p != "" {
+			query.Set("path", p)
+		}
+		if h := stream.Get("httpupgradeSettings.host").String(); h != "" {
+			query.Set("host", h)
+		}
+	case "xhttp", "splithttp":
+		query.Set("type", "xhttp")
+		x := stream.GetJson("xhttpSettings")
+		if x == nil || x.IsNil() {
+			x = stream.GetJson("splithttpSettings")
+		}
+		if x == nil {
+			break
+		}
+		if p := x.Get("path").String(); p != "" {
+			query.Set("path", p)
+		}
+		if h := x.Get("host").String(); h != "" {
+			query.Set("host", h)
+		}
+		if m := x.Get("mode").String(); m != "" {
+			query.Set("mode", m)
+		}
+		if x.Contains("extra") {
+			query.Set("extra", x.GetJson("extra").MustToJsonString())
+		}
+	case "kcp":
+		if h := stream.Get("kcpSettings.header.type").String(); h != "" {
+			query.Set("headerType", h)
+		}
+		if seed := stream.Get("kcpSettings.seed").String(); seed != "" {
+			query.Set("seed", seed)
+		}
+	case "quic":
+		if sec := stream.Get("quicSettings.security").String(); sec != "" {
+			query.Set("quicSecurity", sec)
+		}
+		if key := stream.Get("quicSettings.key").String(); key != "" {
+			query.Set("key", key)
+		}
+		if h := stream.Get("quicSettings.header.type").String(); h != "" {
+			query.Set("headerType", h)
+		}
+	case "http", "h2":
+		query.Set("type", "http")
+		if p := stream.Get("httpSettings.path").String(); p != "" {
+			query.Set("path", p)
+		}
+		if h := stream.Get("httpSettings.host").Strings(); len(h) > 0 {
+			query.Set("host", strings.Join(h, ","))
+		}
+	}
+
+	switch security := stream.Get("security").String(); security {
+	case "tls":
+		query.Set("security", "tls")
+		if sn := stream.Get("tlsSettings.serverName").String(); sn != "" {
+			query.Set("sni", sn)
+		}
+		if alpn := stream.Get("tlsSettings.alpn").Strings(); len(alpn) > 0 {
+			query.Set("alpn", strings.Join(alpn, ","))
+		}
+		if fp := stream.Get("tlsSettings.fingerprint").String(); fp != "" {
+			query.Set("fp", fp)
+		}
+		if gconv.Bool(stream.Get("tlsSettings.allowInsecure").String()) {
+			query.Set("allowInsecure", "1")
+		}
+	case "reality":
+		query.Set("security", "reality")
+		r := stream.GetJson("realitySettings")
+		if r == nil {
+			break
+		}
+		for key, field := range map[string]string{
+			"sni": "serverName",
+			"pbk": "publicKey",
+			"sid": "shortId",
+			"spx": "spiderX",
+			"fp":  "fingerprint",
+		} {
+			if v := r.Get(field).String(); v != "" {
+				query.Set(key, v)
+			}
+		}
+	default:
+		query.Set("security", "none")
+	}
+	return query
+}
+
+// encodeVmess builds a v2rayN style vmess link: "vmess://" + base64(json).
+func encodeVmess(j, stream *gjson.Json, tag string) (string, error) {
+	u := j.GetJson("settings.vnext.0")
+	if u == nil {
+		return "", fmt.Errorf("vmess outbound has no vnext")
+	}
+	query := streamToQuery(stream)
+	v := gjson.New("{}")
+	v.Set("v", "2")
+	v.Set("ps", tag)
+	v.Set("add", u.Get("address").String())
+	v.Set("port", u.Get("port").String())
+	v.Set("id", u.Get("users.0.id").String())
+	v.Set("aid", u.Get("users.0.alterId").String())
+	v.Set("scy", u.Get("users.0.security").String())
+	v.Set("net", query.Get("type"))
+	v.Set("type", "none")
+	if h := query.Get("headerType"); h != "" {
+		v.Set("type", h)
+	}
+	v.Set("host", query.Get("host"))
+	v.Set("path", query.Get("path"))
+	if query.Get("type") == "grpc" {
+		v.Set("path", query.Get("serviceName"))
+	}
+	v.Set("tls", "")
+	if sec := query.Get("security"); sec != "none" {
+		v.Set("tls", sec)
+	}
+	v.Set("sni", query.Get("sni"))
+	v.Set("alpn", query.Get("alpn"))
+	v.Set("fp", query.Get("fp"))
+	return parser.SchemeVmess + base64.StdEncoding.EncodeToString(v.MustToJson()), nil
+}
@@ -1,7 +1,10 @@
 package xray
 
 import (
-	
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
 
 	"github.com/ngwayzinmoe/uri-to-json/pkgs/parser"
 	"github.com/ngwayzinmoe/uri-to-json/pkgs/utils"
@@ -23,11 +26,11 @@ type Hysteria2Out struct {
 	outbound string
 }
 
-// Parse parses the raw hysteria:// URI
-func (that *Hysteria2Out) Parse(rawUri string) {
+// Parse parses the raw hysteria2:// or hy2:// URI
+func (that *Hysteria2Out) Parse(rawUri string) error {
 	that.RawUri = rawUri
 	that.Parser = &parser.ParserHysteria2{}
-	_ = that.Parser.Parse(rawUri)
+	return that.Parser.Parse(rawUri)
 }
 
 func (that *Hysteria2Out) Addr() string {
@@ -85,6 +88,9 @@ func (that *Hysteria2Out) GetOutboundStr() string {
 		stream.Set("tlsSettings.serverName", that.Parser.StreamField.ServerName)
 		stream.Set("tlsSettings.allowInsecure", that.Parser.StreamField.TLSAllowInsecure)
 	}
+	if pin := pinSHA256ToBase64(that.Parser.Config.PinSHA256); pin != "" {
+		stream.Set("tlsSettings.pinnedPeerCertificateChainSha256", []string{pin})
+	}
 
 	// Build final outbound object
 	outObj := gjson.New("{}")
@@ -96,3 +102,12 @@ func (that *Hysteria2Out) GetOutboundStr() string {
 	that.outbound = outObj.MustToJsonString()
 	return that.outbound
 }
+
+// pinSHA256ToBase64 converts a hex certificate hash ("AB:CD:..." or "abcd...") to the base64 form used by xray.
+func pinSHA256ToBase64(pin string) string {
+	b, err := hex.DecodeString(strings.ReplaceAll(pin, ":", ""))
+	if err != nil || len(b) != sha256.Size {
+		return ""
+	}
+	return base64.StdEncoding.EncodeToString(b)
+}
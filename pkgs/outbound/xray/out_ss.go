@@ -59,9 +59,25 @@ type ShadowSocksOut struct {
 	outbound string
 }
 
-func (that *ShadowSocksOut) Parse(rawUri string) {
+func (that *ShadowSocksOut) Parse(rawUri string) error {
 	that.Parser = &parser.ParserSS{}
-	that.Parser.Parse(rawUri)
+	if err := that.Parser.Parse(rawUri); err != nil {
+		return err
+	}
+	return CheckSSPlugin(that.Parser)
+}
+
+// CheckSSPlugin reports plugins xray-core can't replace with its own transport.
+// Only v2ray-plugin in websocket mode is wire compatible with a ws stream.
+func CheckSSPlugin(p *parser.ParserSS) error {
+	switch {
+	case p.Plugin == "":
+		return nil
+	case p.Plugin == parser.SSPluginV2ray && p.Mode == "websocket":
+		return nil
+	default:
+		return parser.NewParseError(parser.ErrUnsupportedTransport, parser.SchemeSS, "plugin", p.Plugin)
+	}
 }
 
 func (that *ShadowSocksOut) Addr() string {
@@ -99,6 +115,9 @@ func (that *ShadowSocksOut) setProtocolAndTag(outStr string) string {
 	j := gjson.New(outStr)
 	j.Set("protocol", "shadowsocks")
 	j.Set("tag", utils.OutboundTag)
+	if n := that.Parser.PluginMux(); n > 0 {
+		j.Set("mux", map[string]interface{}{"enabled": true, "concurrency": n})
+	}
 	return j.MustToJsonString()
 }
 
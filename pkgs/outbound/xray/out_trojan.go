@@ -40,9 +40,9 @@ type TrojanOut struct {
 	outbound string
 }
 
-func (that *TrojanOut) Parse(rawUri string) {
+func (that *TrojanOut) Parse(rawUri string) error {
 	that.Parser = &parser.ParserTrojan{}
-	that.Parser.Parse(rawUri)
+	return that.Parser.Parse(rawUri)
 }
 
 func (that *TrojanOut) Addr() string {
@@ -60,9 +60,9 @@ type VlessOut struct {
 	outbound string
 }
 
-func (that *VlessOut) Parse(rawUri string) {
+func (that *VlessOut) Parse(rawUri string) error {
 	that.Parser = &parser.ParserVless{}
-	that.Parser.Parse(rawUri)
+	return that.Parser.Parse(rawUri)
 }
 
 func (that *VlessOut) Addr() string {
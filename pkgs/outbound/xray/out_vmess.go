@@ -68,9 +68,9 @@ type VmessOut struct {
 	outbound string
 }
 
-func (that *VmessOut) Parse(rawUri string) {
+func (that *VmessOut) Parse(rawUri string) error {
 	that.Parser = &parser.ParserVmess{}
-	that.Parser.Parse(rawUri)
+	return that.Parser.Parse(rawUri)
 }
 
 func (that *VmessOut) Addr() string {
@@ -0,0 +1,144 @@
+package xray
+
+import (
+	"fmt"
+
+	"github.com/ngwayzinmoe/uri-to-json/pkgs/parser"
+	"github.com/ngwayzinmoe/uri-to-json/pkgs/utils"
+	"github.com/gogf/gf/v2/encoding/gjson"
+)
+
+/*
+https://xtls.github.io/config/outbounds/wireguard.html#outboundconfigurationobject
+
+{
+	"secretKey": "PRIVATE_KEY",
+	"address": [
+		"10.0.0.2/32",
+		"fd00::2/128"
+	],
+	"peers": [
+		{
+			"endpoint": "ENDPOINT_ADDR",
+			"publicKey": "PUBLIC_KEY",
+			"preSharedKey": "",
+			"keepAlive": 0,
+			"allowedIPs": ["0.0.0.0/0", "::/0"]
+		}
+	],
+	"kernelMode": false,
+	"mtu": 1420,
+	"reserved": [1, 2, 3],
+	"workers": 2,
+	"domainStrategy": "ForceIP"
+}
+*/
+
+var XrayWireguard string = `{
+	"secretKey": "",
+	"address": [],
+	"peers": [
+		{
+			"endpoint": "",
+			"publicKey": "",
+			"allowedIPs": ["0.0.0.0/0", "::/0"]
+		}
+	],
+	"mtu": 1420
+}`
+
+type WireguardOut struct {
+	RawUri   string
+	Parser   *parser.ParserWirguard
+	outbound string
+}
+
+func (that *WireguardOut) Parse(rawUri string) error {
+	that.Parser = &parser.ParserWirguard{}
+	return that.Parser.Parse(rawUri)
+}
+
+func (that *WireguardOut) Addr() string {
+	if that.Parser == nil {
+		return ""
+	}
+	return that.Parser.GetAddr()
+}
+
+func (that *WireguardOut) Port() int {
+	if that.Parser == nil {
+		return 0
+	}
+	return that.Parser.GetPort()
+}
+
+func (that *WireguardOut) Scheme() string {
+	return parser.SchemeWireguard
+}
+
+func (that *WireguardOut) GetRawUri() string {
+	return that.RawUri
+}
+
+func (that *WireguardOut) getSettings() string {
+	j := gjson.New(XrayWireguard)
+	j.Set("secretKey", that.Parser.PrivateKey)
+	j.Set("address", that.Parser.LocalAddresses())
+
+	peers := []map[string]interface{}{}
+	for _, p := range that.Parser.Peers {
+		peer := map[string]interface{}{
+			"endpoint":   p.Endpoint,
+			"publicKey":  p.PublicKey,
+			"allowedIPs": []string{"0.0.0.0/0", "::/0"},
+		}
+		if len(p.AllowedIPs) > 0 {
+			peer["allowedIPs"] = p.AllowedIPs
+		}
+		if p.PresharedKey != "" {
+			peer["preSharedKey"] = p.PresharedKey
+		}
+		if p.Keepalive > 0 {
+			peer["keepAlive"] = p.Keepalive
+		}
+		peers = append(peers, peer)
+	}
+	j.Set("peers", peers)
+
+	if len(that.Parser.Reserved) > 0 {
+		j.Set("reserved", that.Parser.Reserved)
+	}
+	if that.Parser.MTU > 0 {
+		j.Set("mtu", that.Parser.MTU)
+	}
+	return j.MustToJsonString()
+}
+
+func (that *WireguardOut) setProtocolAndTag(outStr string) string {
+	j := gjson.New(outStr)
+	j.Set("protocol", "wireguard")
+	j.Set("tag", utils.OutboundTag)
+	j.Remove("streamSettings")
+	return j.MustToJsonString()
+}
+
+func (that *WireguardOut) GetOutboundStr() string {
+	if that.Parser.Address == "" && that.Parser.Port == 0 {
+		return ""
+	}
+	if that.outbound == "" {
+		settings := that.getSettings()
+		outStr := fmt.Sprintf(XrayOut, settings, "{}")
+		that.outbound = that.setProtocolAndTag(outStr)
+	}
+	return that.outbound
+}
+
+func TestWireguard() {
+	rawUri := `wireguard://{"PrivateKey":"2B8LLjlXkJ608ct0LD0UnuuR9A2GuZUFBMBQJ9GFn1I=","AddrV4":"172.16.0.2","AddrV6":"2606:4700:110:8dad:87b4:b141:584d:e9dc","DNS":"1.1.1.1","MTU":1280,"PublicKey":"bmXOC+F1FxEMF9dyiK2H5/1SUtzH0JuVo51h2wPfgyo=","AllowedIPs":["0.0.0.0/0","::/0"],"Endpoint":"198.41.222.233:2087","ClientID":"GpxH","DeviceName":"D9D669","Reserved":null,"Address":"198.41.222.233","Port":2087}`
+	wo := &WireguardOut{}
+	wo.Parse(rawUri)
+	o := wo.GetOutboundStr()
+	j := gjson.New(o)
+	fmt.Println(j.MustToJsonIndentString())
+}
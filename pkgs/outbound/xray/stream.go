@@ -13,6 +13,7 @@ import (
 Xray Outbound StreamSettings Full Complete
 Supports:
 - TCP / HTTP / WS / gRPC
+- HTTPUpgrade / XHTTP(SplitHTTP) / mKCP / QUIC / H2
 - TLS / Reality
 - Fingerprint, ALPN, Path, Host, MultiMode
 - Default values preserved
@@ -72,6 +73,43 @@ var XrayStreamGRPC = `{
 	"initial_windows_size": 0
 }`
 
+var XrayStreamHTTPUpgrade = `{
+	"path": "/",
+	"host": ""
+}`
+
+var XrayStreamXHTTP = `{
+	"path": "/",
+	"host": "",
+	"mode": "auto"
+}`
+
+var XrayStreamKCP = `{
+	"mtu": 1350,
+	"tti": 50,
+	"uplinkCapacity": 12,
+	"downlinkCapacity": 100,
+	"congestion": false,
+	"readBufferSize": 2,
+	"writeBufferSize": 2,
+	"header": {
+		"type": "none"
+	}
+}`
+
+var XrayStreamQUIC = `{
+	"security": "none",
+	"key": "",
+	"header": {
+		"type": "none"
+	}
+}`
+
+var XrayStreamH2 = `{
+	"host": [],
+	"path": "/"
+}`
+
 // ---------------- Prepare Stream ----------------
 
 func PrepareStreamString(sf *parser.StreamField) string {
@@ -117,6 +155,54 @@ func PrepareStreamString(sf *parser.StreamField) string {
 			j.Set("multiMode", true)
 		}
 		stream = utils.SetJsonObjectByString("grpcSettings", j.MustToJsonString(), stream)
+	case "httpupgrade":
+		j := gjson.New(XrayStreamHTTPUpgrade)
+		if sf.Path != "" {
+			j.Set("path", sf.Path)
+		}
+		j.Set("host", sf.Host)
+		stream = utils.SetJsonObjectByString("httpupgradeSettings", j.MustToJsonString(), stream)
+	case "xhttp":
+		j := gjson.New(XrayStreamXHTTP)
+		if sf.Path != "" {
+			j.Set("path", sf.Path)
+		}
+		j.Set("host", sf.Host)
+		if sf.XHTTPMode != "" {
+			j.Set("mode", sf.XHTTPMode)
+		}
+		if extra := gjson.New(sf.XHTTPExtra); sf.XHTTPExtra != "" && extra != nil && !extra.IsNil() {
+			j = utils.SetJsonObjectByString("extra", extra.MustToJsonString(), j)
+		}
+		stream = utils.SetJsonObjectByString("xhttpSettings", j.MustToJsonString(), stream)
+	case "kcp":
+		j := gjson.New(XrayStreamKCP)
+		if sf.TCPHeaderType != "" {
+			j.Set("header.type", sf.TCPHeaderType)
+		}
+		if sf.KCPSeed != "" {
+			j.Set("seed", sf.KCPSeed)
+		}
+		stream = utils.SetJsonObjectByString("kcpSettings", j.MustToJsonString(), stream)
+	case "quic":
+		j := gjson.New(XrayStreamQUIC)
+		if sf.QUICSecurity != "" {
+			j.Set("security", sf.QUICSecurity)
+		}
+		j.Set("key", sf.QUICKey)
+		if sf.TCPHeaderType != "" {
+			j.Set("header.type", sf.TCPHeaderType)
+		}
+		stream = utils.SetJsonObjectByString("quicSettings", j.MustToJsonString(), stream)
+	case "http":
+		j := gjson.New(XrayStreamH2)
+		if sf.Host != "" {
+			j.Set("host", strings.Split(sf.Host, ","))
+		}
+		if sf.Path != "" {
+			j.Set("path", sf.Path)
+		}
+		stream = utils.SetJsonObjectByString("httpSettings", j.MustToJsonString(), stream)
 	}
 
 	// ---------------- Security ----------------
@@ -0,0 +1,100 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+/*
+Parse errors are reported as *ParseError, whose Kind is one of the sentinel errors below,
+so callers can categorize rejects with errors.Is:
+
+	if errors.Is(err, parser.ErrBadPort) { ... }
+*/
+
+var (
+	ErrInvalidScheme        = errors.New("invalid scheme")
+	ErrInvalidFormat        = errors.New("invalid format")
+	ErrBadBase64            = errors.New("bad base64")
+	ErrMissingHost          = errors.New("missing host")
+	ErrBadPort              = errors.New("bad port")
+	ErrMissingCredential    = errors.New("missing credential")
+	ErrUnsupportedMethod    = errors.New("unsupported method")
+	ErrUnsupportedTransport = errors.New("unsupported transport")
+	ErrBadKey               = errors.New("bad key")
+)
+
+type ParseError struct {
+	Kind   error  // one of the sentinel errors above
+	Scheme string // scheme of the uri being parsed, eg. "vless://"
+	Field  string // offending field, eg. "port"
+	Value  string // offending value
+	Err    error  // underlying error if any
+}
+
+func NewParseError(kind error, scheme, field, value string, err ...error) *ParseError {
+	e := &ParseError{
+		Kind:   kind,
+		Scheme: scheme,
+		Field:  field,
+		Value:  value,
+	}
+	if len(err) > 0 {
+		e.Err = err[0]
+	}
+	return e
+}
+
+func (that *ParseError) Error() string {
+	s := that.Kind.Error()
+	if scheme := strings.TrimSuffix(that.Scheme, "://"); scheme != "" {
+		s = scheme + ": " + s
+	}
+	if that.Field != "" {
+		s += fmt.Sprintf(" (%s=%q)", that.Field, that.Value)
+	}
+	if that.Err != nil {
+		s += ": " + that.Err.Error()
+	}
+	return s
+}
+
+func (that *ParseError) Is(target error) bool {
+	return that.Kind == target
+}
+
+func (that *ParseError) Unwrap() error {
+	return that.Err
+}
+
+// ErrorKind returns the sentinel kind of err, or nil if err is not a *ParseError.
+func ErrorKind(err error) error {
+	var pe *ParseError
+	if errors.As(err, &pe) {
+		return pe.Kind
+	}
+	return nil
+}
+
+func checkScheme(rawUri string, schemes ...string) error {
+	for _, scheme := range schemes {
+		if strings.HasPrefix(rawUri, scheme) {
+			return nil
+		}
+	}
+	return NewParseError(ErrInvalidScheme, schemes[0], "scheme", GetVpnScheme(rawUri))
+}
+
+// checkHostPort validates host and converts rawPort to a port number.
+func checkHostPort(scheme, host, rawPort string) (int, error) {
+	if host == "" {
+		return 0, NewParseError(ErrMissingHost, scheme, "host", host)
+	}
+	port, err := strconv.Atoi(rawPort)
+	if err != nil || port <= 0 || port > 65535 {
+		return 0, NewParseError(ErrBadPort, scheme, "port", rawPort, err)
+	}
+	return port, nil
+}
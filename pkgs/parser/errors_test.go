@@ -0,0 +1,46 @@
+package parser
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseErrorKinds(t *testing.T) {
+	kinds := []error{
+		ErrInvalidScheme, ErrInvalidFormat, ErrBadBase64, ErrMissingHost, ErrBadPort,
+		ErrMissingCredential, ErrUnsupportedMethod, ErrUnsupportedTransport, ErrBadKey,
+	}
+	for _, c := range []struct {
+		rawUri string
+		kind   error
+	}{
+		{"socks://user@example.com:1080", ErrInvalidScheme},
+		{"vless://uuid@example.com:0?type=tcp", ErrBadPort},
+		{"trojan://pass@example.com:99999", ErrBadPort},
+		{"vless://uuid@:443", ErrMissingHost},
+		{"vless://example.com:443?type=tcp", ErrMissingCredential},
+		{"trojan://example.com:443", ErrMissingCredential},
+		{"vless://uuid@example.com:443?type=carrier-pigeon", ErrUnsupportedTransport},
+		{"ss://" + b64("rot13:pass") + "@1.2.3.4:8388", ErrUnsupportedMethod},
+		{"ss://2022-blake3-aes-128-gcm:c2hvcnQ%3D@1.2.3.4:443", ErrBadKey},
+		{"vmess://!!!not-base64!!!", ErrBadBase64},
+		{"wireguard://key@1.2.3.4:51820", ErrMissingCredential},
+		{"wireguard://key@1.2.3.4:51820?publickey=pub&mtu=big", ErrInvalidFormat},
+		{"wireguard://key@1.2.3.4:51820?publickey=pub&reserved=1,x,3", ErrInvalidFormat},
+		{"hysteria2://auth@h.example.com:443?up=fast", ErrInvalidFormat},
+	} {
+		_, err := parseFields(c.rawUri)
+		if err == nil {
+			t.Errorf("%s: no error, want %s", c.rawUri, c.kind)
+			continue
+		}
+		if !errors.Is(err, c.kind) || ErrorKind(err) != c.kind {
+			t.Errorf("%s: %s, want %s", c.rawUri, err, c.kind)
+		}
+		for _, kind := range kinds {
+			if kind != c.kind && errors.Is(err, kind) {
+				t.Errorf("%s: %s also matches %s", c.rawUri, err, kind)
+			}
+		}
+	}
+}
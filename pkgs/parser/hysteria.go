@@ -0,0 +1,100 @@
+package parser
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+/*
+https://v1.hysteria.network/docs/uri-scheme/
+
+hysteria://host:port?protocol=udp&auth=123456&peer=sni.domain&insecure=1&upmbps=100&downmbps=100&alpn=hysteria&obfs=xplus&obfsParam=123456#remarks
+
+hysteria: ['protocol', 'auth', 'peer', 'insecure', 'upmbps', 'downmbps', 'alpn', 'obfs', 'obfsParam']
+*/
+
+type ParserHysteria struct {
+	Address   string
+	Port      int
+	Protocol  string // udp | wechat-video | faketcp
+	Auth      string
+	UpMbps    int
+	DownMbps  int
+	OBFS      string
+	OBFSParam string
+	Name      string
+	Group     string
+
+	*StreamField
+}
+
+func (that *ParserHysteria) Parse(rawUri string) error {
+	if err := checkScheme(rawUri, SchemeHysteria); err != nil {
+		return err
+	}
+	u, err := url.Parse(rawUri)
+	if err != nil {
+		return NewParseError(ErrInvalidFormat, SchemeHysteria, "", "", err)
+	}
+	that.Address = u.Hostname()
+	if that.Port, err = checkHostPort(SchemeHysteria, that.Address, u.Port()); err != nil {
+		return err
+	}
+
+	that.Name = decodeRemark(u.Fragment)
+	query := u.Query()
+	that.Protocol = query.Get("protocol")
+	if that.Protocol == "" {
+		that.Protocol = "udp"
+	}
+	that.Auth = query.Get("auth")
+	if that.UpMbps, err = parseMbps(SchemeHysteria, "upmbps", query.Get("upmbps")); err != nil {
+		return err
+	}
+	if that.DownMbps, err = parseMbps(SchemeHysteria, "downmbps", query.Get("downmbps")); err != nil {
+		return err
+	}
+	that.OBFS = query.Get("obfs")
+	that.OBFSParam = query.Get("obfsParam")
+
+	that.StreamField = &StreamField{
+		Network:          "udp",
+		StreamSecurity:   "tls",
+		ServerName:       query.Get("peer"),
+		TLSALPN:          query.Get("alpn"),
+		TLSAllowInsecure: query.Get("insecure"),
+	}
+	return nil
+}
+
+// parseMbps accepts "100", "100mbps" or "100 Mbps", empty means unlimited.
+func parseMbps(scheme, field, value string) (int, error) {
+	v := strings.TrimSpace(strings.ToLower(value))
+	v = strings.TrimSpace(strings.TrimSuffix(v, "mbps"))
+	if v == "" {
+		return 0, nil
+	}
+	mbps, err := strconv.Atoi(v)
+	if err != nil || mbps < 0 {
+		return 0, NewParseError(ErrInvalidFormat, scheme, field, value, err)
+	}
+	return mbps, nil
+}
+
+func (that *ParserHysteria) GetAddr() string {
+	return that.Address
+}
+
+func (that *ParserHysteria) GetPort() int {
+	return that.Port
+}
+
+func (that *ParserHysteria) Show() {
+	fmt.Printf("addr: %s, port: %d, protocol: %s, auth: %s\n",
+		that.Address,
+		that.Port,
+		that.Protocol,
+		that.Auth)
+}
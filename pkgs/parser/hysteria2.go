@@ -10,35 +10,48 @@ import (
 
 // Hysteria2Config represents a Hysteria2 server configuration
 type Hysteria2Config struct {
-	Server   string `json:"server"`
-	Port     int    `json:"port"`
-	Auth     string `json:"auth"`
-	SNI      string `json:"sni,omitempty"`
-	Insecure bool   `json:"insecure"`
-	OBFS     string `json:"obfs,omitempty"`
-	OBFSPass string `json:"obfs_password,omitempty"`
-	Remark   string `json:"remark,omitempty"`
+	Server    string `json:"server"`
+	Port      int    `json:"port"`
+	Auth      string `json:"auth"`
+	SNI       string `json:"sni,omitempty"`
+	Insecure  bool   `json:"insecure"`
+	OBFS      string `json:"obfs,omitempty"`
+	OBFSPass  string `json:"obfs_password,omitempty"`
+	PinSHA256 string `json:"pin_sha256,omitempty"`
+	UpMbps    int    `json:"up_mbps,omitempty"`
+	DownMbps  int    `json:"down_mbps,omitempty"`
+	Remark    string `json:"remark,omitempty"`
 }
 
-// ParserHysteria2 parses hysteria:// URIs
+// ParserHysteria2 parses hysteria2:// and hy2:// URIs
 type ParserHysteria2 struct {
 	Config      Hysteria2Config
 	StreamField *StreamField // for outbound use
+	Name        string       // same as Config.Remark
+	Group       string
 }
 
-// Parse parses a hysteria:// URI into Hysteria2Config
+/*
+Parse parses a hysteria2 URI into Hysteria2Config
+
+https://v2.hysteria.network/docs/developers/URI-Scheme/
+
+hysteria2://[auth@]hostname[:port]/?[key=value]&[key=value]...
+keys: obfs, obfs-password, sni, insecure, pinSHA256, up, down
+*/
 func (p *ParserHysteria2) Parse(rawUri string) error {
-	if !strings.HasPrefix(rawUri, "hysteria://") {
-		return fmt.Errorf("invalid hysteria URI")
+	if err := checkScheme(rawUri, SchemeHysteria2, SchemeHysteria2Short); err != nil {
+		return err
 	}
 
 	// remove scheme
-	rawUri = strings.TrimPrefix(rawUri, "hysteria://")
+	rawUri = strings.TrimPrefix(rawUri, SchemeHysteria2)
+	rawUri = strings.TrimPrefix(rawUri, SchemeHysteria2Short)
 
 	// split fragment (#...)
 	remark := ""
 	if idx := strings.Index(rawUri, "#"); idx != -1 {
-		remark, _ = url.QueryUnescape(rawUri[idx+1:])
+		remark = decodeRemark(rawUri[idx+1:])
 		rawUri = rawUri[:idx]
 	}
 
@@ -48,38 +61,58 @@ func (p *ParserHysteria2) Parse(rawUri string) error {
 		queryStr = rawUri[idx+1:]
 		rawUri = rawUri[:idx]
 	}
-
-	// parse user info and host:port
-	userHost := strings.SplitN(rawUri, "@", 2)
-	if len(userHost) != 2 {
-		return fmt.Errorf("invalid hysteria URI, missing auth or host")
+	rawUri = strings.TrimSuffix(rawUri, "/")
+
+	// auth is optional
+	auth := ""
+	hostPort := rawUri
+	if idx := strings.LastIndex(rawUri, "@"); idx != -1 {
+		auth, _ = url.PathUnescape(rawUri[:idx])
+		hostPort = rawUri[idx+1:]
 	}
 
-	auth := userHost[0]
-	hostPort := userHost[1]
-
 	host, portStr, err := netSplitHostPort(hostPort)
 	if err != nil {
-		return err
+		// port defaults to 443
+		host, portStr = hostPort, "443"
+	}
+	host = strings.TrimSuffix(strings.TrimPrefix(host, "["), "]")
+	// multi-port servers ("443,1000-2000"), the first port is used
+	if idx := strings.IndexAny(portStr, ",-"); idx != -1 {
+		portStr = portStr[:idx]
 	}
-	port, err := strconv.Atoi(portStr)
+	port, err := checkHostPort(SchemeHysteria2, host, portStr)
 	if err != nil {
-		return fmt.Errorf("invalid port: %v", err)
+		return err
 	}
 
-	// parse query parameters
+	// parse query parameters, some links separate them by ";"
+	if strings.Contains(queryStr, ";") && !strings.Contains(queryStr, "&") {
+		queryStr = strings.ReplaceAll(queryStr, ";", "&")
+	}
 	qValues, _ := url.ParseQuery(queryStr)
 	insecure := qValues.Get("insecure") == "1" || qValues.Get("allow_insecure") == "1"
 
 	p.Config = Hysteria2Config{
-		Server:   host,
-		Port:     port,
-		Auth:     auth,
-		SNI:      qValues.Get("sni"),
-		Insecure: insecure,
-		OBFS:     qValues.Get("obfs"),
-		OBFSPass: qValues.Get("obfs-password"),
-		Remark:   remark,
+		Server:    host,
+		Port:      port,
+		Auth:      auth,
+		SNI:       qValues.Get("sni"),
+		Insecure:  insecure,
+		OBFS:      qValues.Get("obfs"),
+		OBFSPass:  qValues.Get("obfs-password"),
+		PinSHA256: qValues.Get("pinSHA256"),
+		Remark:    remark,
+	}
+	p.Name = remark
+	if p.Config.OBFS != "" && p.Config.OBFS != "salamander" {
+		return NewParseError(ErrUnsupportedMethod, SchemeHysteria2, "obfs", p.Config.OBFS)
+	}
+	if p.Config.UpMbps, err = parseMbps(SchemeHysteria2, "up", firstNonEmpty(qValues.Get("up"), qValues.Get("upmbps"))); err != nil {
+		return err
+	}
+	if p.Config.DownMbps, err = parseMbps(SchemeHysteria2, "down", firstNonEmpty(qValues.Get("down"), qValues.Get("downmbps"))); err != nil {
+		return err
 	}
 
 	// StreamField setup
@@ -93,6 +126,15 @@ func (p *ParserHysteria2) Parse(rawUri string) error {
 	return nil
 }
 
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
 // GetAddr returns server address
 func (p *ParserHysteria2) GetAddr() string {
 	return p.Config.Server
@@ -0,0 +1,314 @@
+package parser
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+/*
+Normalize parses rawUri and re-emits it in one canonical form per scheme, so the same node is spelled once:
+
+	vless/trojan/tuic/hysteria/hysteria2/wireguard: lower case host, sorted query without empty keys, percent encoded remark
+	ss:     SIP002, base64url(method:password) or plain percent encoded keys for shadowsocks 2022
+	ssr:    base64url of the whole link, params base64url encoded
+	vmess:  base64 of the v2rayN json with sorted keys
+
+hy2:// and wg:// become hysteria2:// and wireguard://, a wg-quick config with a single peer becomes a wireguard:// uri.
+*/
+func Normalize(rawUri string) (string, error) {
+	rawUri = strings.TrimSpace(rawUri)
+	if IsWireguardConf(rawUri) && !strings.Contains(rawUri, "://") {
+		rawUri = SchemeWireguard + rawUri
+	}
+	switch GetVpnScheme(rawUri) {
+	case SchemeVless:
+		p := &ParserVless{}
+		if err := p.Parse(rawUri); err != nil {
+			return "", err
+		}
+		query := streamQuery(p.StreamField)
+		setQuery(query, "encryption", p.Encryption)
+		setQuery(query, "flow", p.Flow)
+		return buildUri(SchemeVless, escapeComponent(p.UUID), p.Address, p.Port, query, p.Name), nil
+	case SchemeTrojan:
+		p := &ParserTrojan{}
+		if err := p.Parse(rawUri); err != nil {
+			return "", err
+		}
+		return buildUri(SchemeTrojan, escapeComponent(p.Password), p.Address, p.Port, streamQuery(p.StreamField), p.Name), nil
+	case SchemeSS:
+		p := &ParserSS{}
+		if err := p.Parse(rawUri); err != nil {
+			return "", err
+		}
+		userInfo := base64.RawURLEncoding.EncodeToString([]byte(p.Method + ":" + p.Password))
+		if _, ok := SS2022KeyLen[p.Method]; ok {
+			// SIP002: 2022 keys are percent encoded instead of base64.
+			userInfo = escapeComponent(p.Method) + ":" + escapeComponent(p.Password)
+		}
+		query := url.Values{}
+		if p.Plugin != "" {
+			query.Set("plugin", strings.Trim(p.Plugin+";"+p.PluginOptsString(), ";"))
+		}
+		return buildUri(SchemeSS, userInfo, p.Address, p.Port, query, p.Name), nil
+	case SchemeSSR:
+		p := &ParserSSR{}
+		if err := p.Parse(rawUri); err != nil {
+			return "", err
+		}
+		return normalizeSSR(p), nil
+	case SchemeVmess:
+		p := &ParserVmess{}
+		if err := p.Parse(rawUri); err != nil {
+			return "", err
+		}
+		return normalizeVmess(p)
+	case SchemeHysteria:
+		p := &ParserHysteria{}
+		if err := p.Parse(rawUri); err != nil {
+			return "", err
+		}
+		query := url.Values{}
+		setQuery(query, "protocol", p.Protocol)
+		setQuery(query, "auth", p.Auth)
+		setQuery(query, "peer", p.ServerName)
+		setQuery(query, "alpn", p.TLSALPN)
+		setQuery(query, "obfs", p.OBFS)
+		setQuery(query, "obfsParam", p.OBFSParam)
+		setMbps(query, "upmbps", p.UpMbps)
+		setMbps(query, "downmbps", p.DownMbps)
+		if isTrue(p.TLSAllowInsecure) {
+			query.Set("insecure", "1")
+		}
+		return buildUri(SchemeHysteria, "", p.Address, p.Port, query, p.Name), nil
+	case SchemeHysteria2, SchemeHysteria2Short:
+		p := &ParserHysteria2{}
+		if err := p.Parse(rawUri); err != nil {
+			return "", err
+		}
+		query := url.Values{}
+		setQuery(query, "sni", p.Config.SNI)
+		setQuery(query, "obfs", p.Config.OBFS)
+		setQuery(query, "obfs-password", p.Config.OBFSPass)
+		setQuery(query, "pinSHA256", p.Config.PinSHA256)
+		setMbps(query, "up", p.Config.UpMbps)
+		setMbps(query, "down", p.Config.DownMbps)
+		if p.Config.Insecure {
+			query.Set("insecure", "1")
+		}
+		return buildUri(SchemeHysteria2, escapeComponent(p.Config.Auth), p.Config.Server, p.Config.Port, query, p.Name), nil
+	case SchemeTUIC:
+		p := &ParserTUIC{}
+		if err := p.Parse(rawUri); err != nil {
+			return "", err
+		}
+		query := url.Values{}
+		setQuery(query, "congestion_control", p.CongestionControl)
+		setQuery(query, "udp_relay_mode", p.UDPRelayMode)
+		setQuery(query, "sni", p.ServerName)
+		setQuery(query, "alpn", p.TLSALPN)
+		if isTrue(p.TLSAllowInsecure) {
+			query.Set("allow_insecure", "1")
+		}
+		if p.DisableSNI {
+			query.Set("disable_sni", "1")
+		}
+		userInfo := escapeComponent(p.UUID)
+		if p.Password != "" {
+			userInfo += ":" + escapeComponent(p.Password)
+		}
+		return buildUri(SchemeTUIC, userInfo, p.Address, p.Port, query, p.Name), nil
+	case SchemeWireguard, SchemeWireguardShort:
+		p := &ParserWirguard{}
+		if err := p.Parse(rawUri); err != nil {
+			return "", err
+		}
+		return normalizeWireguard(p)
+	default:
+		return "", NewParseError(ErrInvalidScheme, GetVpnScheme(rawUri), "scheme", GetVpnScheme(rawUri))
+	}
+}
+
+// escapeComponent percent encodes everything but unreserved characters, spaces as %20.
+func escapeComponent(s string) string {
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
+
+func buildUri(scheme, userInfo, host string, port int, query url.Values, name string) string {
+	var b strings.Builder
+	b.WriteString(scheme)
+	if userInfo != "" {
+		b.WriteString(userInfo)
+		b.WriteString("@")
+	}
+	b.WriteString(net.JoinHostPort(strings.ToLower(host), strconv.Itoa(port)))
+	if len(query) > 0 {
+		// Encode sorts the keys.
+		b.WriteString("?")
+		b.WriteString(strings.ReplaceAll(query.Encode(), "+", "%20"))
+	}
+	if name != "" {
+		b.WriteString("#")
+		b.WriteString(escapeComponent(name))
+	}
+	return b.String()
+}
+
+func setQuery(query url.Values, key, value string) {
+	if value != "" {
+		query.Set(key, value)
+	}
+}
+
+func setMbps(query url.Values, key string, mbps int) {
+	if mbps > 0 {
+		query.Set(key, strconv.Itoa(mbps))
+	}
+}
+
+func isTrue(s string) bool {
+	b, _ := strconv.ParseBool(s)
+	return b
+}
+
+// streamQuery translates a StreamField back into the query keys read by the vless and trojan parsers.
+func streamQuery(sf *StreamField) url.Values {
+	query := url.Values{}
+	setQuery(query, "type", sf.Network)
+	setQuery(query, "security", sf.StreamSecurity)
+	setQuery(query, "path", sf.Path)
+	setQuery(query, "host", sf.Host)
+	setQuery(query, "headerType", sf.TCPHeaderType)
+	switch sf.Network {
+	case "grpc":
+		setQuery(query, "serviceName", sf.GRPCServiceName)
+		setQuery(query, "mode", sf.GRPCMultiMode)
+	case "xhttp":
+		setQuery(query, "mode", sf.XHTTPMode)
+		setQuery(query, "extra", sf.XHTTPExtra)
+	case "kcp":
+		setQuery(query, "seed", sf.KCPSeed)
+	case "quic":
+		setQuery(query, "quicSecurity", sf.QUICSecurity)
+		setQuery(query, "key", sf.QUICKey)
+	}
+	setQuery(query, "sni", sf.ServerName)
+	setQuery(query, "alpn", sf.TLSALPN)
+	setQuery(query, "fp", sf.Fingerprint)
+	setQuery(query, "pbk", sf.RealityPublicKey)
+	setQuery(query, "sid", sf.RealityShortId)
+	setQuery(query, "spx", sf.RealitySpiderX)
+	setQuery(query, "packetEncoding", sf.PacketEncoding)
+	if isTrue(sf.TLSAllowInsecure) {
+		query.Set("allowInsecure", "1")
+	}
+	return query
+}
+
+// normalizeSSR builds ssr://base64url(host:port:proto:method:obfs:base64url(password)/?params).
+func normalizeSSR(p *ParserSSR) string {
+	enc := func(s string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(s))
+	}
+	params := []string{}
+	for _, kv := range [][2]string{
+		{"obfsparam", p.OBFSParam},
+		{"protoparam", p.ProtoParam},
+		{"remarks", p.Name},
+		{"group", p.Group},
+	} {
+		if kv[1] != "" {
+			params = append(params, kv[0]+"="+enc(kv[1]))
+		}
+	}
+	body := strings.Join([]string{
+		strings.ToLower(p.Address),
+		strconv.Itoa(p.Port),
+		p.Proto,
+		p.Method,
+		p.OBFS,
+		enc(p.Password),
+	}, ":") + "/?" + strings.Join(params, "&")
+	return SchemeSSR + enc(body)
+}
+
+// normalizeVmess builds the v2rayN link, path/type/host carry the transport specific fields like the parser expects.
+func normalizeVmess(p *ParserVmess) (string, error) {
+	sf := p.StreamField
+	v := map[string]string{
+		"v":    "2",
+		"ps":   p.Name,
+		"add":  strings.ToLower(p.Address),
+		"port": strconv.Itoa(p.Port),
+		"id":   p.UUID,
+		"aid":  p.AID,
+		"scy":  p.Security,
+		"net":  sf.Network,
+		"type": sf.TCPHeaderType,
+		"host": sf.Host,
+		"path": sf.Path,
+		"tls":  sf.StreamSecurity,
+		"sni":  sf.ServerName,
+		"alpn": sf.TLSALPN,
+		"fp":   sf.Fingerprint,
+	}
+	switch sf.Network {
+	case "grpc":
+		v["path"], v["type"] = sf.GRPCServiceName, sf.GRPCMultiMode
+	case "kcp":
+		v["path"] = sf.KCPSeed
+	case "quic":
+		v["host"], v["path"] = sf.QUICSecurity, sf.QUICKey
+	case "xhttp":
+		v["type"] = sf.XHTTPMode
+		if sf.XHTTPExtra != "" {
+			v["extra"] = sf.XHTTPExtra
+		}
+	}
+	if sf.StreamSecurity == "reality" {
+		v["pbk"], v["sid"], v["spx"] = sf.RealityPublicKey, sf.RealityShortId, sf.RealitySpiderX
+	}
+	if isTrue(sf.TLSAllowInsecure) {
+		v["allowInsecure"] = "1"
+	}
+	// map keys are sorted by encoding/json.
+	content, err := json.Marshal(v)
+	if err != nil {
+		return "", NewParseError(ErrInvalidFormat, SchemeVmess, "", "", err)
+	}
+	return SchemeVmess + base64.StdEncoding.EncodeToString(content), nil
+}
+
+// normalizeWireguard builds the wireguard:// uri form, which only describes a single peer.
+func normalizeWireguard(p *ParserWirguard) (string, error) {
+	if len(p.Peers) != 1 {
+		return "", NewParseError(ErrInvalidFormat, SchemeWireguard, "peer", strconv.Itoa(len(p.Peers)),
+			fmt.Errorf("%d peers don't fit in a wireguard uri", len(p.Peers)))
+	}
+	peer := p.Peers[0]
+	query := url.Values{}
+	query.Set("publickey", peer.PublicKey)
+	setQuery(query, "presharedkey", peer.PresharedKey)
+	setQuery(query, "address", strings.Join(p.LocalAddresses(), ","))
+	setQuery(query, "allowedips", strings.Join(peer.AllowedIPs, ","))
+	setQuery(query, "dns", p.DNS)
+	if len(p.Reserved) > 0 {
+		reserved := make([]string, len(p.Reserved))
+		for i, n := range p.Reserved {
+			reserved[i] = strconv.Itoa(n)
+		}
+		query.Set("reserved", strings.Join(reserved, ","))
+	}
+	if p.MTU > 0 {
+		query.Set("mtu", strconv.Itoa(p.MTU))
+	}
+	if peer.Keepalive > 0 {
+		query.Set("keepalive", strconv.Itoa(peer.Keepalive))
+	}
+	return buildUri(SchemeWireguard, escapeComponent(p.PrivateKey), peer.Address, peer.Port, query, p.Name), nil
+}
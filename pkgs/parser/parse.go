@@ -3,9 +3,10 @@ package parser
 import (
 	"net/url"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/gvcgo/goutils/pkgs/crypt"
-	"github.com/gvcgo/goutils/pkgs/gtui"
 )
 
 const (
@@ -16,6 +17,11 @@ const (
 	SchemeVmess     string = "vmess://"
 	SchemeWireguard string = "wireguard://"
 	SchemeHysteria2 string = "hysteria2://" // [၁] Hysteria2 ထည့်လိုက်ပါ
+	SchemeTUIC      string = "tuic://"
+
+	SchemeHysteria       string = "hysteria://"
+	SchemeHysteria2Short string = "hy2://"
+	SchemeWireguardShort string = "wg://"
 )
 
 // SafeBase64Decode handles standard and URL-safe Base64 with proper padding
@@ -34,6 +40,28 @@ func SafeBase64Decode(str string) string {
 	return crypt.DecodeBase64(str)
 }
 
+// isDecodedText reports whether a base64 decoded value looks like real text,
+// so plain passwords that happen to be valid base64 are left untouched.
+func isDecodedText(s string) bool {
+	if s == "" || !utf8.ValidString(s) {
+		return false
+	}
+	for _, r := range s {
+		if !unicode.IsPrint(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// decodeRemark percent decodes the remark of a node, it is kept as is when it isn't escaped properly.
+func decodeRemark(s string) string {
+	if r, err := url.PathUnescape(s); err == nil {
+		s = r
+	}
+	return strings.TrimSpace(s)
+}
+
 func GetVpnScheme(rawUri string) string {
 	sep := "://"
 	if !strings.Contains(rawUri, sep) {
@@ -57,70 +85,50 @@ func HandleQuery(rawUri string) (result string) {
 	return
 }
 
-func ParseRawUri(rawUri string) (result string) {
-	// [၂] VMess အတွက် base64 ကို အရင်ကိုင်တွယ်မယ်
-	if strings.HasPrefix(rawUri, SchemeVmess) {
-		// VMess processing
-		b64Part := strings.Split(rawUri, "://")[1]
-		if r := SafeBase64Decode(b64Part); r != "" {
-			result = SchemeVmess + r
-		} else {
-			result = rawUri
-		}
-		return
-	}
-
-	// [၃] Character တွေကို သန့်စင်မယ်
-	if strings.Contains(rawUri, "\u0026") {
-		rawUri = strings.ReplaceAll(rawUri, "\u0026", "&")
-	}
-	
-	// Remark တွေမှာ space ပါရင် error မတက်အောင် decode အရင်လုပ်မယ်
-	tempUri, _ := url.QueryUnescape(rawUri)
-	r, err := url.Parse(tempUri)
-	result = tempUri
-	if err != nil {
-		gtui.PrintError(err)
-		return
-	}
+/*
+ParseRawUri decodes the legacy spellings of share links, anything else is returned as is:
 
-	// [၄] Hysteria2 သို့မဟုတ် Vless ဆိုရင် UUID တွေကို Base64 decode မလုပ်မိအောင် ကျော်ခဲ့မယ်
-	scheme := GetVpnScheme(rawUri)
-	if scheme == SchemeVless || scheme == SchemeHysteria2 || scheme == SchemeTrojan {
-		result = HandleQuery(tempUri)
-		return
-	}
-
-	// Shadowsocks (SS) အတွက်သာ Base64 decoding logic ကို သုံးမယ်
-	result = tempUri
-	host := r.Host
-	uname := r.User.Username()
-	passw, hasPassword := r.User.Password()
+	vmess://base64(json)                           -> vmess://json
+	ss://base64(method:password@host:port)#remark  -> ss://method:password@host:port#remark
+	ssr://base64(host:port:...)                    -> ssr://host:port:...
 
-	if !strings.Contains(tempUri, "@") {
-		// ss://[base64] format မျိုးအတွက်
-		if hostDecrypted := SafeBase64Decode(host); hostDecrypted != "" {
-			result = strings.ReplaceAll(tempUri, host, hostDecrypted)
+The link stays escaped, the parsers unescape every part with url.Parse.
+The parsers accept the legacy spellings too, so uris don't need to go through ParseRawUri before parsing.
+*/
+func ParseRawUri(rawUri string) (result string) {
+	rawUri = strings.TrimSpace(rawUri)
+	switch GetVpnScheme(rawUri) {
+	case SchemeVmess:
+		body := strings.TrimPrefix(rawUri, SchemeVmess)
+		if strings.HasPrefix(body, "{") {
+			return rawUri
 		}
-	} else if uname != "" && !hasPassword && !strings.Contains(uname, "-") {
-		// ss://[base64]@host:port format မျိုးအတွက်
-		if unameDecrypted := SafeBase64Decode(uname); unameDecrypted != "" {
-			result = strings.ReplaceAll(tempUri, uname, unameDecrypted)
+		if decoded, err := decodeKey(body); err == nil && strings.HasPrefix(strings.TrimSpace(string(decoded)), "{") {
+			return SchemeVmess + string(decoded)
 		}
-	} else if hasPassword {
-		// ss://method:[base64_password]@host:port format မျိုးအတွက်
-		// user:pass format (အကယ်၍ password က base64 ဖြစ်နေခဲ့လျှင်)
-		if passwDecrypted := SafeBase64Decode(passw); passwDecrypted != "" {
-			result = strings.ReplaceAll(tempUri, passw, passwDecrypted)
+		return rawUri
+	case SchemeSS:
+		if !strings.Contains(rawUri, "@") {
+			return decodeBody(SchemeSS, rawUri)
 		}
+	case SchemeSSR:
+		return decodeBody(SchemeSSR, rawUri)
 	}
+	return rawUri
+}
 
-	if strings.Contains(result, "%") {
-		result, _ = url.QueryUnescape(result)
+// decodeBody decodes a link whose body after scheme is base64, the fragment is kept as is.
+func decodeBody(scheme, rawUri string) string {
+	body, frag := strings.TrimPrefix(rawUri, scheme), ""
+	if i := strings.Index(body, "#"); i >= 0 {
+		body, frag = body[:i], body[i:]
 	}
-	result = HandleQuery(result)
-	return
+	if s, err := url.PathUnescape(body); err == nil {
+		body = s
+	}
+	decoded, err := decodeKey(strings.TrimSpace(body))
+	if err != nil || !isDecodedText(string(decoded)) || !strings.Contains(string(decoded), ":") {
+		return rawUri
+	}
+	return scheme + string(decoded) + frag
 }
-
-
-
@@ -4,6 +4,7 @@ import (
 	"encoding/base64"
 	"fmt"
 	"net/url"
+	"sort"
 	"strconv"
 	"strings"
 )
@@ -29,11 +30,34 @@ var SSMethod map[string]struct{} = map[string]struct{}{
 	"xchacha20":                     {},
 }
 
+// SIP003 plugins the outbound builders know how to translate.
+const (
+	SSPluginObfs  string = "obfs-local"
+	SSPluginV2ray string = "v2ray-plugin"
+)
+
+var ssPluginAliases = map[string]string{
+	"obfs-local":   SSPluginObfs,
+	"simple-obfs":  SSPluginObfs,
+	"obfs":         SSPluginObfs,
+	"v2ray-plugin": SSPluginV2ray,
+	"v2ray":        SSPluginV2ray,
+}
+
+// Key length in bytes of the shadowsocks 2022 methods.
+var SS2022KeyLen map[string]int = map[string]int{
+	"2022-blake3-aes-128-gcm":       16,
+	"2022-blake3-aes-256-gcm":       32,
+	"2022-blake3-chacha20-poly1305": 32,
+}
+
 type ParserSS struct {
 	Address  string
 	Port     int
 	Method   string
 	Password string
+	Name     string
+	Group    string
 
 	Host     string
 	Mode     string
@@ -43,75 +67,276 @@ type ParserSS struct {
 	OBFS     string
 	OBFSHost string
 
+	// Shadowsocks 2022 only, Password is "iPSK:...:uPSK" for multi-user relays.
+	IdentityKeys []string
+	UserKey      string
+
+	// PluginOpts holds the SIP003 options of Plugin, eg. obfs=http;obfs-host=example.com
+	PluginOpts map[string]string
+
 	*StreamField
 }
 
-// Added to prevent error
+func (that *ParserSS) Parse(rawUri string) error {
+	if err := checkScheme(rawUri, SchemeSS); err != nil {
+		return err
+	}
+	rawUri, err := that.handleSS(rawUri)
+	if err != nil {
+		return err
+	}
 
-func (that *ParserSS) Parse(rawUri string) {
-	rawUri = that.handleSS(rawUri)
-	
 	u, err := url.Parse(rawUri)
 	if err != nil {
-		return
+		return NewParseError(ErrInvalidFormat, SchemeSS, "", "", err)
 	}
 
 	that.StreamField = &StreamField{}
+	that.Name = decodeRemark(u.Fragment)
 	that.Address = u.Hostname()
-	that.Port, _ = strconv.Atoi(u.Port())
+	if that.Port, err = checkHostPort(SchemeSS, that.Address, u.Port()); err != nil {
+		return err
+	}
 
 	// ✅ SIP002 format handles (ss://base64(method:password)@addr:port)
-	if u.User != nil {
-		userInfo := u.User.String()
-		
-		// Decode base64 if user info is still encoded
-		if decoded, err := base64.URLEncoding.DecodeString(userInfo); err == nil {
-			userInfo = string(decoded)
-		} else if decoded, err := base64.StdEncoding.DecodeString(userInfo); err == nil {
-			userInfo = string(decoded)
+	if u.User == nil {
+		return NewParseError(ErrMissingCredential, SchemeSS, "userinfo", "")
+	}
+	// String() escapes "/" again, base64 needs it raw.
+	userInfo := u.User.String()
+	if s, err := url.PathUnescape(userInfo); err == nil {
+		userInfo = s
+	}
+
+	// Decode base64 if user info is still encoded
+	if decoded, err := base64.URLEncoding.DecodeString(userInfo); err == nil {
+		userInfo = string(decoded)
+	} else if decoded, err := base64.StdEncoding.DecodeString(userInfo); err == nil {
+		userInfo = string(decoded)
+	} else if decoded, err := base64.RawURLEncoding.DecodeString(userInfo); err == nil {
+		userInfo = string(decoded)
+	}
+
+	parts := strings.SplitN(userInfo, ":", 2)
+	if len(parts) == 2 {
+		that.Method = parts[0]
+		that.Password = parts[1]
+	} else {
+		that.Method = parts[0]
+	}
+
+	if that.Method == "rc4" {
+		that.Method = "rc4-md5"
+	}
+	if _, ok := SSMethod[that.Method]; !ok {
+		return NewParseError(ErrUnsupportedMethod, SchemeSS, "method", that.Method)
+	}
+
+	if err := that.parse2022Keys(); err != nil {
+		return err
+	}
+	return that.parsePlugin(u.Query())
+}
+
+/*
+parse2022Keys validates the base64 keys of shadowsocks 2022 methods.
+The password is either the user key, or identity keys followed by the user key, separated by ":".
+Keys are normalized to padded standard base64.
+*/
+func (that *ParserSS) parse2022Keys() error {
+	keyLen, ok := SS2022KeyLen[that.Method]
+	if !ok {
+		return nil
+	}
+	if that.Password == "" {
+		return NewParseError(ErrMissingCredential, SchemeSS, "password", "")
+	}
+	keys := strings.Split(that.Password, ":")
+	for i, key := range keys {
+		decoded, err := decodeKey(key)
+		if err != nil {
+			return NewParseError(ErrBadBase64, SchemeSS, "password", key, err)
+		}
+		if len(decoded) != keyLen {
+			return NewParseError(ErrBadKey, SchemeSS, "password", key,
+				fmt.Errorf("%s needs a %d byte key, got %d", that.Method, keyLen, len(decoded)))
+		}
+		keys[i] = base64.StdEncoding.EncodeToString(decoded)
+	}
+	that.IdentityKeys = keys[:len(keys)-1]
+	that.UserKey = keys[len(keys)-1]
+	that.Password = strings.Join(keys, ":")
+	return nil
+}
+
+// decodeKey decodes standard or url-safe base64, padded or not.
+func decodeKey(key string) ([]byte, error) {
+	key = strings.TrimRight(key, "=")
+	if decoded, err := base64.RawStdEncoding.DecodeString(key); err == nil {
+		return decoded, nil
+	}
+	return base64.RawURLEncoding.DecodeString(key)
+}
+
+/*
+parsePlugin parses the SIP003 plugin string: plugin=name;opt=val;flag
+Some links put the options in the query instead, eg. plugin=v2ray-plugin&mode=websocket
+*/
+func (that *ParserSS) parsePlugin(query url.Values) error {
+	that.PluginOpts = map[string]string{}
+	for _, key := range []string{"obfs", "obfs-host", "mode", "host", "path", "mux", "tls"} {
+		if v := query.Get(key); v != "" {
+			that.PluginOpts[key] = v
 		}
+	}
+	plugin := splitPluginOpts(query.Get("plugin"))
+	if len(plugin) == 0 || plugin[0] == "" {
+		that.PluginOpts = map[string]string{}
+		return nil
+	}
+	for _, opt := range plugin[1:] {
+		key, value, ok := strings.Cut(opt, "=")
+		if !ok {
+			value = "true"
+		}
+		that.PluginOpts[unescapePluginOpt(key)] = unescapePluginOpt(value)
+	}
 
-		parts := strings.SplitN(userInfo, ":", 2)
-		if len(parts) == 2 {
-			that.Method = parts[0]
-			that.Password = parts[1]
-		} else {
-			that.Method = parts[0]
+	that.Plugin = plugin[0]
+	if name, ok := ssPluginAliases[that.Plugin]; ok {
+		that.Plugin = name
+	}
+	that.Host = that.PluginOpts["host"]
+	that.Mode = that.PluginOpts["mode"]
+	that.Mux = that.PluginOpts["mux"]
+	that.Path = that.PluginOpts["path"]
+
+	switch that.Plugin {
+	case SSPluginObfs:
+		that.OBFS = that.PluginOpts["obfs"]
+		that.OBFSHost = that.PluginOpts["obfs-host"]
+		if that.OBFS != "http" && that.OBFS != "tls" {
+			return NewParseError(ErrUnsupportedTransport, SchemeSS, "obfs", that.OBFS)
+		}
+	case SSPluginV2ray:
+		if that.Mode == "" {
+			that.Mode = "websocket"
+		}
+		switch that.Mode {
+		case "websocket":
+			that.StreamField.Network = "ws"
+		case "quic":
+			that.StreamField.Network = "quic"
+		default:
+			return NewParseError(ErrUnsupportedTransport, SchemeSS, "mode", that.Mode)
 		}
+		if that.Path == "" {
+			that.Path = "/"
+		}
+		that.StreamField.Path = that.Path
+		that.StreamField.Host = that.Host
+		that.StreamField.Mux = that.Mux
+		if _, ok := that.PluginOpts["tls"]; ok && that.PluginOpts["tls"] != "false" {
+			that.StreamField.StreamSecurity = "tls"
+			that.StreamField.ServerName = that.Host
+		}
+	}
+	return nil
+}
 
-		if that.Method == "rc4" {
-			that.Method = "rc4-md5"
+// PluginOptsString returns PluginOpts in SIP003 form with sorted keys.
+func (that *ParserSS) PluginOptsString() string {
+	keys := make([]string, 0, len(that.PluginOpts))
+	for k := range that.PluginOpts {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	opts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		v := that.PluginOpts[k]
+		if v == "true" && k == "tls" {
+			opts = append(opts, escapePluginOpt(k))
+			continue
 		}
-		if _, ok := SSMethod[that.Method]; !ok {
-			that.Method = "none"
+		opts = append(opts, escapePluginOpt(k)+"="+escapePluginOpt(v))
+	}
+	return strings.Join(opts, ";")
+}
+
+// PluginMux returns the mux concurrency of v2ray-plugin, which is 1 unless disabled.
+func (that *ParserSS) PluginMux() int {
+	if that.Plugin != SSPluginV2ray {
+		return 0
+	}
+	switch that.Mux {
+	case "0", "false":
+		return 0
+	}
+	if n, err := strconv.Atoi(that.Mux); err == nil && n > 0 {
+		return n
+	}
+	return 1
+}
+
+// splitPluginOpts splits on ";" while honoring the SIP003 backslash escape.
+func splitPluginOpts(s string) (result []string) {
+	var b strings.Builder
+	escaped := false
+	for _, r := range s {
+		switch {
+		case escaped:
+			b.WriteRune('\\')
+			b.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == ';':
+			result = append(result, b.String())
+			b.Reset()
+		default:
+			b.WriteRune(r)
 		}
 	}
+	if b.Len() > 0 || len(result) > 0 {
+		result = append(result, b.String())
+	}
+	return
+}
 
-	query := u.Query()
-	that.Host = query.Get("host")
-	that.Mode = query.Get("mode")
-	that.Mux = query.Get("mux")
-	that.Path = query.Get("path")
-	that.Plugin = query.Get("plugin")
-	that.OBFS = query.Get("obfs")
-	that.OBFSHost = query.Get("obfs-host")
+var pluginOptReplacer = strings.NewReplacer(`\\`, `\`, `\;`, `;`, `\=`, `=`)
+
+func unescapePluginOpt(s string) string {
+	return pluginOptReplacer.Replace(s)
 }
 
-func (that *ParserSS) handleSS(rawUri string) string {
+func escapePluginOpt(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `;`, `\;`, `=`, `\=`).Replace(s)
+}
+
+func (that *ParserSS) handleSS(rawUri string) (string, error) {
 	// Handle non-standard prefix and URL encoding
 	rawUri = strings.ReplaceAll(rawUri, "#ss#\u00261@", "@")
-	
-	// Pre-check for Base64 in standard SIP002 URIs
+
+	// Pre-check for Base64 in legacy URIs (ss://base64(method:password@addr:port)#remark)
 	if strings.Contains(rawUri, "ss://") && !strings.Contains(rawUri, "@") {
 		return that.decodeBase64IfNeeded(rawUri)
 	}
-	return rawUri
+
+	// base64 and 2022 keys may hold a raw "/", which url.Parse would take for the path.
+	body := strings.TrimPrefix(rawUri, SchemeSS)
+	if i := strings.Index(body, "#"); i >= 0 {
+		body = body[:i]
+	}
+	if i := strings.LastIndex(body, "@"); i >= 0 && strings.Contains(body[:i], "/") {
+		rawUri = SchemeSS + strings.ReplaceAll(body[:i], "/", "%2F") + strings.TrimPrefix(rawUri, SchemeSS+body[:i])
+	}
+	return rawUri, nil
 }
 
-func (that *ParserSS) decodeBase64IfNeeded(rawUri string) string {
+func (that *ParserSS) decodeBase64IfNeeded(rawUri string) (string, error) {
 	const prefix = "ss://"
 	if !strings.HasPrefix(rawUri, prefix) {
-		return rawUri
+		return rawUri, nil
 	}
 
 	data := rawUri[len(prefix):]
@@ -126,17 +351,12 @@ func (that *ParserSS) decodeBase64IfNeeded(rawUri string) string {
 		data = s
 	}
 
-	// Add padding if needed
-	if m := len(data) % 4; m != 0 {
-		data += strings.Repeat("=", 4-m)
-	}
-
-	decoded, err := base64.StdEncoding.DecodeString(data)
-	if err != nil {
-		return rawUri
+	decoded := SafeBase64Decode(data)
+	if decoded == "" || !strings.Contains(decoded, "@") {
+		return rawUri, NewParseError(ErrBadBase64, SchemeSS, "userinfo", data)
 	}
 
-	return prefix + string(decoded) + frag
+	return prefix + decoded + frag, nil
 }
 
 func (that *ParserSS) GetAddr() string { return that.Address }
@@ -42,12 +42,18 @@ type ParserSSR struct {
 	Proto      string
 	OBFSParam  string
 	ProtoParam string
+	Name       string // base64 decoded remarks
+	Group      string // base64 decoded group
 
 	*StreamField
 }
 
-func (that *ParserSSR) Parse(rawUri string) {
-	r := strings.ReplaceAll(rawUri, SchemeSSR, "")
+func (that *ParserSSR) Parse(rawUri string) error {
+	if err := checkScheme(rawUri, SchemeSSR); err != nil {
+		return err
+	}
+	// ssr links are base64 as a whole.
+	r := strings.ReplaceAll(decodeBody(SchemeSSR, rawUri), SchemeSSR, "")
 	vList := strings.Split(r, "?")
 	if len(vList) == 2 {
 		that.parseMethod(vList[0])
@@ -57,16 +63,23 @@ func (that *ParserSSR) Parse(rawUri string) {
 		if len(vList) == 2 {
 			that.parseMethod(vList[0])
 			that.parseParams("remarks=" + vList[1])
+		} else {
+			that.parseMethod(r)
 		}
 	}
 
+	var err error
+	if that.Port, err = checkHostPort(SchemeSSR, that.Address, gconv.String(that.Port)); err != nil {
+		return err
+	}
 	if _, ok := SSRMethod[that.Method]; !ok {
-		that.Method = "rc4-md5"
+		return NewParseError(ErrUnsupportedMethod, SchemeSSR, "method", that.Method)
 	}
 	if _, ok := SSROBFS[that.OBFS]; !ok {
 		that.OBFS = "plain"
 	}
 	that.StreamField = &StreamField{}
+	return nil
 }
 
 func (that *ParserSSR) parseParams(s string) {
@@ -82,6 +95,8 @@ func (that *ParserSSR) parseParams(s string) {
 		if that.ProtoParam != "" {
 			that.ProtoParam = crypt.DecodeBase64(that.ProtoParam)
 		}
+		that.Name = decodeRemark(SafeBase64Decode(u.Query().Get("remarks")))
+		that.Group = decodeRemark(SafeBase64Decode(u.Query().Get("group")))
 	}
 }
 
@@ -18,7 +18,44 @@ type StreamField struct {
 	RealityPublicKey string
 	PacketEncoding   string
 	UoT bool
+
+	XHTTPMode    string // xhttp: auto, packet-up, stream-up, stream-one
+	XHTTPExtra   string // xhttp: raw json of the extra settings
+	KCPSeed      string
+	QUICSecurity string
+	QUICKey      string
 }
 
 
 
+
+// Networks that the outbound builders know how to emit.
+var StreamNetworks map[string]struct{} = map[string]struct{}{
+	"":            {},
+	"tcp":         {},
+	"ws":          {},
+	"grpc":        {},
+	"httpupgrade": {},
+	"xhttp":       {},
+	"kcp":         {},
+	"quic":        {},
+	"http":        {},
+}
+
+// Other spellings of the networks above found in share links.
+var streamNetworkAliases = map[string]string{
+	"raw":       "tcp",
+	"splithttp": "xhttp",
+	"mkcp":      "kcp",
+	"h2":        "http",
+}
+
+func (that *StreamField) checkNetwork(scheme string) error {
+	if n, ok := streamNetworkAliases[that.Network]; ok {
+		that.Network = n
+	}
+	if _, ok := StreamNetworks[that.Network]; !ok {
+		return NewParseError(ErrUnsupportedTransport, scheme, "type", that.Network)
+	}
+	return nil
+}
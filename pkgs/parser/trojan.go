@@ -5,60 +5,75 @@ import (
 	"fmt"
 	"net/url"
 	"os"
-	"strconv"
-
-	"github.com/gvcgo/goutils/pkgs/gtui"
 )
 
 /*
-trojan: ['allowInsecure', 'peer', 'sni', 'type', 'path', 'security', 'headerType']
+trojan: ['allowInsecure', 'peer', 'sni', 'type', 'path', 'security', 'headerType', 'host', 'serviceName', 'mode', 'fp', 'alpn', 'pbk', 'sid', 'spx']
 */
 
 type ParserTrojan struct {
 	Address  string
 	Port     int
 	Password string
+	Name     string
+	Group    string
 
 	*StreamField
 }
 
-func (that *ParserTrojan) Parse(rawUri string) {
-	if u, err := url.Parse(rawUri); err == nil {
-		that.Address = u.Hostname()
-		that.Port, _ = strconv.Atoi(u.Port())
-		that.Password = u.User.Username()
+func (that *ParserTrojan) Parse(rawUri string) error {
+	if err := checkScheme(rawUri, SchemeTrojan); err != nil {
+		return err
+	}
+	u, err := url.Parse(rawUri)
+	if err != nil {
+		return NewParseError(ErrInvalidFormat, SchemeTrojan, "", "", err)
+	}
+	that.Address = u.Hostname()
+	if that.Port, err = checkHostPort(SchemeTrojan, that.Address, u.Port()); err != nil {
+		return err
+	}
+	that.Password = u.User.Username()
+	if that.Password == "" {
+		return NewParseError(ErrMissingCredential, SchemeTrojan, "password", that.Password)
+	}
 
-		query := u.Query()
+	that.Name = decodeRemark(u.Fragment)
+	query := u.Query()
 
-		that.StreamField = &StreamField{
-			Network:          query.Get("type"),
-			Host:             query.Get("peer"),
-			Path:             query.Get("path"),
-			StreamSecurity:   query.Get("security"),
-			ServerName:       query.Get("sni"),
-			TCPHeaderType:    query.Get("headerType"),
-			TLSAllowInsecure: query.Get("allowInsecure"),
-		}
-	} else {
-		gtui.PrintError(err)
-		fmt.Println(rawUri)
-		return
+	that.StreamField = &StreamField{
+		Network:          query.Get("type"),
+		StreamSecurity:   query.Get("security"),
+		Path:             query.Get("path"),
+		Host:             query.Get("host"),
+		GRPCServiceName:  query.Get("serviceName"),
+		GRPCMultiMode:    query.Get("mode"),
+		ServerName:       firstNonEmpty(query.Get("sni"), query.Get("peer")),
+		TLSALPN:          query.Get("alpn"),
+		TLSAllowInsecure: firstNonEmpty(query.Get("allowInsecure"), query.Get("insecure")),
+		Fingerprint:      query.Get("fp"),
+		RealityShortId:   query.Get("sid"),
+		RealitySpiderX:   query.Get("spx"),
+		RealityPublicKey: query.Get("pbk"),
+		TCPHeaderType:    query.Get("headerType"),
+		XHTTPMode:        query.Get("mode"),
+		XHTTPExtra:       query.Get("extra"),
+		KCPSeed:          query.Get("seed"),
+		QUICSecurity:     query.Get("quicSecurity"),
+		QUICKey:          query.Get("key"),
 	}
 
-	if that.StreamField.TLSAllowInsecure != "" && that.StreamField.ServerName != "" {
-		if that.Network == "" {
-			that.Network = "tcp"
-		}
-		if that.StreamSecurity == "" {
-			that.StreamSecurity = "tls"
-		}
-		if that.Host == "" {
-			that.Host = that.ServerName
-		}
-		if that.ServerName == "" {
-			that.ServerName = that.Address
-		}
+	// trojan runs over tls unless told otherwise.
+	if that.Network == "" {
+		that.Network = "tcp"
+	}
+	if that.StreamSecurity == "" {
+		that.StreamSecurity = "tls"
+	}
+	if that.StreamSecurity == "tls" && that.ServerName == "" {
+		that.ServerName = firstNonEmpty(that.Host, that.Address)
 	}
+	return that.StreamField.checkNetwork(SchemeTrojan)
 }
 
 func (that *ParserTrojan) GetAddr() string {
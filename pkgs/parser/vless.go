@@ -5,11 +5,10 @@ import (
 	"fmt"
 	"net/url"
 	"os"
-	"strconv"
 )
 
 /*
-vless: ['security', 'type', 'sni', 'path', 'encryption', 'headerType', 'packetEncoding', 'serviceName', 'mode', 'flow', 'alpn', 'host', 'fp', 'pbk', 'sid', 'spx']
+vless: ['security', 'type', 'sni', 'path', 'encryption', 'headerType', 'packetEncoding', 'serviceName', 'mode', 'flow', 'alpn', 'host', 'fp', 'pbk', 'sid', 'spx', 'extra', 'seed', 'quicSecurity', 'key']
 */
 
 type ParserVless struct {
@@ -18,41 +17,60 @@ type ParserVless struct {
 	UUID       string
 	Encryption string
 	Flow       string
+	Name       string
+	Group      string
 	*StreamField
 }
 
-func (that *ParserVless) Parse(rawUri string) {
-	if r, err := url.Parse(rawUri); err == nil {
-		that.Address = r.Hostname()
-		that.Port, _ = strconv.Atoi(r.Port())
-		that.UUID = r.User.Username()
-		query := r.Query()
-		that.Encryption = query.Get("encryption")
-		if that.Encryption == "" {
-			that.Encryption = "none"
-		}
-		that.Flow = query.Get("flow")
-		if that.Flow == "xtls-rprx-direct-udp443" {
-			that.Flow = "xtls-rprx-vision-udp443"
-		}
+func (that *ParserVless) Parse(rawUri string) error {
+	if err := checkScheme(rawUri, SchemeVless); err != nil {
+		return err
+	}
+	r, err := url.Parse(rawUri)
+	if err != nil {
+		return NewParseError(ErrInvalidFormat, SchemeVless, "", "", err)
+	}
+	that.Address = r.Hostname()
+	if that.Port, err = checkHostPort(SchemeVless, that.Address, r.Port()); err != nil {
+		return err
+	}
+	that.UUID = r.User.Username()
+	if that.UUID == "" {
+		return NewParseError(ErrMissingCredential, SchemeVless, "uuid", that.UUID)
+	}
+	that.Name = decodeRemark(r.Fragment)
+	query := r.Query()
+	that.Encryption = query.Get("encryption")
+	if that.Encryption == "" {
+		that.Encryption = "none"
+	}
+	that.Flow = query.Get("flow")
+	if that.Flow == "xtls-rprx-direct-udp443" {
+		that.Flow = "xtls-rprx-vision-udp443"
+	}
 
-		that.StreamField = &StreamField{
-			Network:          query.Get("type"),
-			StreamSecurity:   query.Get("security"),
-			Path:             query.Get("path"),
-			Host:             query.Get("host"),
-			GRPCServiceName:  query.Get("serviceName"),
-			GRPCMultiMode:    query.Get("mode"),
-			ServerName:       query.Get("sni"),
-			TLSALPN:          query.Get("alpn"),
-			Fingerprint:      query.Get("fp"),
-			RealityShortId:   query.Get("sid"),
-			RealitySpiderX:   query.Get("spx"),
-			RealityPublicKey: query.Get("pbk"),
-			PacketEncoding:   query.Get("packetEncoding"),
-			TCPHeaderType:    query.Get("headerType"),
-		}
+	that.StreamField = &StreamField{
+		Network:          query.Get("type"),
+		StreamSecurity:   query.Get("security"),
+		Path:             query.Get("path"),
+		Host:             query.Get("host"),
+		GRPCServiceName:  query.Get("serviceName"),
+		GRPCMultiMode:    query.Get("mode"),
+		ServerName:       query.Get("sni"),
+		TLSALPN:          query.Get("alpn"),
+		Fingerprint:      query.Get("fp"),
+		RealityShortId:   query.Get("sid"),
+		RealitySpiderX:   query.Get("spx"),
+		RealityPublicKey: query.Get("pbk"),
+		PacketEncoding:   query.Get("packetEncoding"),
+		TCPHeaderType:    query.Get("headerType"),
+		XHTTPMode:        query.Get("mode"),
+		XHTTPExtra:       query.Get("extra"),
+		KCPSeed:          query.Get("seed"),
+		QUICSecurity:     query.Get("quicSecurity"),
+		QUICKey:          query.Get("key"),
 	}
+	return that.StreamField.checkNetwork(SchemeVless)
 }
 
 func (that *ParserVless) GetAddr() string {
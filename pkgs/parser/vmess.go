@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 
 	"github.com/gogf/gf/v2/encoding/gjson"
@@ -11,6 +12,10 @@ import (
 
 /*
 vmess: ['v', 'ps', 'add', 'port', 'aid', 'scy', 'net', 'type', 'tls', 'id', 'sni', 'host', 'path', 'alpn', 'security', 'skip-cert-verify', 'fp', 'test_name', 'serverPort', 'nation']
+extra: ['serviceName', 'mode', 'sid', 'spx', 'pbk', 'allowInsecure', 'insecure', 'seed', 'quicSecurity', 'key', 'extra']
+
+port and aid may be numbers or strings, alpn may be a string or an array.
+For grpc path/type carry serviceName/mode, for kcp path is the seed, for quic host/path are security/key.
 */
 
 type ParserVmess struct {
@@ -27,32 +32,56 @@ type ParserVmess struct {
 	SkipCertVerify bool
 	TestName       string
 	V              string
+	Name           string // same as PS
+	Group          string
 
 	*StreamField
 }
 
-func (that *ParserVmess) Parse(rawUri string) {
-	r := strings.ReplaceAll(rawUri, SchemeVmess, "")
-	j := gjson.New(r)
-	if j == nil {
-		return
+func (that *ParserVmess) Parse(rawUri string) error {
+	if err := checkScheme(rawUri, SchemeVmess); err != nil {
+		return err
+	}
+	r := strings.TrimSpace(strings.TrimPrefix(rawUri, SchemeVmess))
+	if !strings.HasPrefix(r, "{") {
+		decoded := SafeBase64Decode(r)
+		if !strings.HasPrefix(strings.TrimSpace(decoded), "{") {
+			return NewParseError(ErrBadBase64, SchemeVmess, "body", r)
+		}
+		r = decoded
+	}
+	if !json.Valid([]byte(r)) {
+		return NewParseError(ErrInvalidFormat, SchemeVmess, "body", r)
 	}
+	j := gjson.New(r)
 	that.Address = j.Get("add").String()
-	if !strings.Contains(that.Address, ".") {
-		that.Address = ""
-		return
+	if !strings.Contains(that.Address, ".") && !strings.Contains(that.Address, ":") {
+		return NewParseError(ErrMissingHost, SchemeVmess, "add", that.Address)
+	}
+	var err error
+	if that.Port, err = checkHostPort(SchemeVmess, that.Address, strings.TrimSpace(j.Get("port").String())); err != nil {
+		return err
 	}
-	that.Port = j.Get("port").Int()
 	that.UUID = j.Get("id").String()
-	that.AID = j.Get("aid").String()
-	that.Security = j.Get("security").String()
-	that.Security = j.Get("security").String()
-	if that.Security == "" {
-		that.Security = j.Get("scy").String()
+	if that.UUID == "" {
+		return NewParseError(ErrMissingCredential, SchemeVmess, "id", that.UUID)
+	}
+	that.AID = "0"
+	if aid := strings.TrimSpace(j.Get("aid").String()); aid != "" {
+		if n, err := strconv.Atoi(aid); err == nil {
+			that.AID = strconv.Itoa(n)
+		} else {
+			return NewParseError(ErrInvalidFormat, SchemeVmess, "aid", aid, err)
+		}
+	}
+	that.Security = j.Get("scy").String()
+	if sec := j.Get("security").String(); that.Security == "" && sec != "tls" && sec != "reality" {
+		that.Security = sec
 	}
 
 	that.Nation = j.Get("nation").String()
 	that.PS = j.Get("ps").String()
+	that.Name = strings.TrimSpace(that.PS)
 	that.ServerPort = j.Get("serverPort").String()
 	that.SkipCertVerify = j.Get("skip-cert-verify").Bool()
 	that.TestName = j.Get("test_name").String()
@@ -61,18 +90,58 @@ func (that *ParserVmess) Parse(rawUri string) {
 	that.StreamField = &StreamField{}
 	that.StreamField.Network = j.Get("net").String()
 	that.StreamField.StreamSecurity = j.Get("tls").String()
+	if sec := j.Get("security").String(); that.StreamSecurity == "" && (sec == "tls" || sec == "reality") {
+		that.StreamField.StreamSecurity = sec
+	}
 	that.StreamField.Path = j.Get("path").String()
 	that.StreamField.Host = j.Get("host").String()
-	// that.StreamField.GRPCServiceName = j.GetString("serviceName")
-	// that.StreamField.GRPCMultiMode = j.GetString("mode")
 	that.StreamField.ServerName = j.Get("sni").String()
 	that.StreamField.TCPHeaderType = j.Get("type").String()
-	that.StreamField.TLSALPN = j.Get("alpn").String()
 	that.StreamField.Fingerprint = j.Get("fp").String()
+	if alpn := j.Get("alpn"); alpn.IsSlice() {
+		that.StreamField.TLSALPN = strings.Join(alpn.Strings(), ",")
+	} else {
+		that.StreamField.TLSALPN = alpn.String()
+	}
+	for _, key := range []string{"allowInsecure", "skip-cert-verify", "insecure"} {
+		if j.Get(key).Bool() {
+			that.StreamField.TLSAllowInsecure = "1"
+		}
+	}
+
+	that.StreamField.RealityShortId = j.Get("sid").String()
+	that.StreamField.RealitySpiderX = j.Get("spx").String()
+	that.StreamField.RealityPublicKey = j.Get("pbk").String()
 
-	// that.StreamField.RealityShortId = j.GetString("sid")
-	// that.StreamField.RealitySpiderX = j.GetString("spx")
-	// that.StreamField.RealityPublicKey = j.GetString("pbk")
+	// v2rayN reuses path/type/host for the transport specific fields.
+	that.StreamField.GRPCServiceName = j.Get("serviceName").String()
+	that.StreamField.GRPCMultiMode = j.Get("mode").String()
+	switch that.StreamField.Network {
+	case "grpc":
+		if that.GRPCServiceName == "" {
+			that.GRPCServiceName = that.StreamField.Path
+		}
+		if that.GRPCMultiMode == "" {
+			that.GRPCMultiMode = that.TCPHeaderType
+		}
+		that.TCPHeaderType = ""
+	case "kcp", "mkcp":
+		that.KCPSeed = firstNonEmpty(j.Get("seed").String(), that.StreamField.Path)
+	case "quic":
+		that.QUICSecurity = firstNonEmpty(j.Get("quicSecurity").String(), that.Host)
+		that.QUICKey = firstNonEmpty(j.Get("key").String(), that.StreamField.Path)
+	case "xhttp", "splithttp":
+		that.XHTTPMode = firstNonEmpty(j.Get("mode").String(), that.TCPHeaderType)
+		if extra := j.Get("extra"); !extra.IsNil() {
+			if extra.IsMap() {
+				that.XHTTPExtra = gjson.New(extra.Map()).MustToJsonString()
+			} else {
+				that.XHTTPExtra = extra.String()
+			}
+		}
+		that.TCPHeaderType = ""
+	}
+	return that.StreamField.checkNetwork(SchemeVmess)
 }
 
 func (that *ParserVmess) GetAddr() string {
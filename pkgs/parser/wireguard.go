@@ -1,12 +1,15 @@
 package parser
 
 import (
+	"bufio"
 	"fmt"
+	"net"
+	"net/url"
+	"strconv"
 	"strings"
 
+	"encoding/base64"
 	"encoding/json"
-
-	"github.com/gvcgo/goutils/pkgs/gtui"
 )
 
 /*
@@ -21,8 +24,38 @@ Endpoint   string   `koanf,json:"endpoint"`
 ClientID   string   `koanf,json:"client_id"`
 DeviceName string   `koanf,json:"device_name"`
 Reserved   []int    `koanf,json:"reserved"`
+
+Besides the json blob above, the following forms are accepted:
+
+wireguard://privatekey@host:port?publickey=&address=&reserved=&mtu=&presharedkey=&keepalive=#name
+wg://privatekey@host:port?...
+
+wireguard:// followed by a standard wg-quick config:
+
+[Interface]
+PrivateKey = ...
+Address = 10.0.0.2/32, fd00::2/128
+DNS = 1.1.1.1
+MTU = 1420
+
+[Peer]
+PublicKey = ...
+PresharedKey = ...
+AllowedIPs = 0.0.0.0/0, ::/0
+Endpoint = host:port
+PersistentKeepalive = 25
 */
 
+type WireguardPeer struct {
+	PublicKey    string   `koanf,json:"public_key"`
+	PresharedKey string   `koanf,json:"pre_shared_key"`
+	AllowedIPs   []string `koanf,json:"allowed_ips"`
+	Endpoint     string   `koanf,json:"endpoint"`
+	Keepalive    int      `koanf,json:"keepalive"`
+	Address      string   `koanf,json:"address"`
+	Port         int      `koanf,json:"port"`
+}
+
 type ParserWirguard struct {
 	PrivateKey string   `koanf,json:"private_key"`
 	AddrV4     string   `koanf,json:"addr_v4"`
@@ -37,15 +70,283 @@ type ParserWirguard struct {
 	Reserved   []int    `koanf,json:"reserved"`
 	Address    string   `koanf,json:"address"`
 	Port       int      `koanf,json:"port"`
+	Name       string   `koanf,json:"name"`
+	Group      string   `koanf,json:"group"`
+
+	// Peers holds every peer, the first one is mirrored to PublicKey, Endpoint, AllowedIPs, Address and Port.
+	Peers []*WireguardPeer `koanf,json:"peers"`
+}
+
+// IsWireguardConf reports whether content looks like a wg-quick config.
+func IsWireguardConf(content string) bool {
+	return strings.Contains(content, "[Interface]")
+}
+
+func (that *ParserWirguard) Parse(rawUri string) (err error) {
+	body := rawUri
+	for _, scheme := range []string{SchemeWireguard, SchemeWireguardShort} {
+		if strings.HasPrefix(strings.TrimSpace(body), scheme) {
+			body = strings.TrimPrefix(strings.TrimSpace(body), scheme)
+			break
+		}
+	}
+	body = strings.TrimSpace(body)
+
+	switch {
+	case strings.HasPrefix(body, "{"):
+		if err = json.Unmarshal([]byte(body), that); err != nil {
+			return NewParseError(ErrInvalidFormat, SchemeWireguard, "", "", err)
+		}
+	case IsWireguardConf(body):
+		err = that.parseConf(body)
+	default:
+		err = that.parseUri(body)
+	}
+	if err != nil {
+		return err
+	}
+	if err = that.setPeers(); err != nil {
+		return err
+	}
+	if that.PrivateKey == "" {
+		return NewParseError(ErrMissingCredential, SchemeWireguard, "private_key", "")
+	}
+	return nil
+}
+
+// parseUri parses privatekey@host:port?query#name.
+// Keys are base64 and may contain "/" or "+", so url.Parse can't be used directly.
+func (that *ParserWirguard) parseUri(body string) error {
+	if i := strings.Index(body, "#"); i >= 0 {
+		that.Name = decodeRemark(body[i+1:])
+		body = body[:i]
+	}
+	rawQuery := ""
+	if i := strings.Index(body, "?"); i >= 0 {
+		body, rawQuery = body[:i], body[i+1:]
+	}
+	i := strings.LastIndex(body, "@")
+	if i < 0 {
+		return NewParseError(ErrMissingCredential, SchemeWireguard, "private_key", "")
+	}
+	privateKey, err := url.PathUnescape(body[:i])
+	if err != nil {
+		return NewParseError(ErrInvalidFormat, SchemeWireguard, "private_key", body[:i], err)
+	}
+	that.PrivateKey = privateKey
+	that.Endpoint = strings.TrimSuffix(body[i+1:], "/")
+
+	// "+" in base64 keys must not become a space.
+	query, err := url.ParseQuery(strings.ReplaceAll(rawQuery, "+", "%2B"))
+	if err != nil {
+		return NewParseError(ErrInvalidFormat, SchemeWireguard, "query", rawQuery, err)
+	}
+	get := func(keys ...string) string {
+		for _, k := range keys {
+			if v := query.Get(k); v != "" {
+				return v
+			}
+		}
+		return ""
+	}
+
+	that.PublicKey = get("publickey", "public_key", "peer_public_key", "publicKey")
+	that.setAddresses(splitList(get("address", "ip", "local_address")))
+	that.DNS = get("dns")
+	if r := get("reserved"); r != "" {
+		if that.Reserved, err = parseReserved(r); err != nil {
+			return err
+		}
+	}
+	if m := get("mtu"); m != "" {
+		if that.MTU, err = strconv.Atoi(m); err != nil {
+			return NewParseError(ErrInvalidFormat, SchemeWireguard, "mtu", m, err)
+		}
+	}
+	peer := &WireguardPeer{
+		PublicKey:    that.PublicKey,
+		PresharedKey: get("presharedkey", "pre_shared_key", "psk", "preSharedKey"),
+		AllowedIPs:   splitList(get("allowedips", "allowed_ips", "allowedIPs")),
+		Endpoint:     that.Endpoint,
+	}
+	if k := get("keepalive", "persistentkeepalive", "persistent_keepalive"); k != "" {
+		if peer.Keepalive, err = strconv.Atoi(k); err != nil {
+			return NewParseError(ErrInvalidFormat, SchemeWireguard, "keepalive", k, err)
+		}
+	}
+	that.Peers = []*WireguardPeer{peer}
+	return nil
+}
+
+// parseConf parses a wg-quick style config with one [Interface] and one or more [Peer] sections.
+func (that *ParserWirguard) parseConf(body string) (err error) {
+	var (
+		section   string
+		peer      *WireguardPeer
+		addresses []string
+	)
+	scanner := bufio.NewScanner(strings.NewReader(body))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = strings.ToLower(strings.Trim(line, "[]"))
+			if section == "peer" {
+				peer = &WireguardPeer{}
+				that.Peers = append(that.Peers, peer)
+			}
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return NewParseError(ErrInvalidFormat, SchemeWireguard, "line", line)
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		switch section {
+		case "interface":
+			switch key {
+			case "privatekey":
+				that.PrivateKey = value
+			case "address":
+				addresses = append(addresses, splitList(value)...)
+			case "dns":
+				that.DNS = value
+			case "mtu":
+				if that.MTU, err = strconv.Atoi(value); err != nil {
+					return NewParseError(ErrInvalidFormat, SchemeWireguard, "mtu", value, err)
+				}
+			case "reserved":
+				if that.Reserved, err = parseReserved(value); err != nil {
+					return err
+				}
+			}
+		case "peer":
+			switch key {
+			case "publickey":
+				peer.PublicKey = value
+			case "presharedkey":
+				peer.PresharedKey = value
+			case "allowedips":
+				peer.AllowedIPs = append(peer.AllowedIPs, splitList(value)...)
+			case "endpoint":
+				peer.Endpoint = value
+			case "persistentkeepalive":
+				if peer.Keepalive, err = strconv.Atoi(value); err != nil {
+					return NewParseError(ErrInvalidFormat, SchemeWireguard, "persistent_keepalive", value, err)
+				}
+			}
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return NewParseError(ErrInvalidFormat, SchemeWireguard, "", "", err)
+	}
+	if len(that.Peers) == 0 {
+		return NewParseError(ErrInvalidFormat, SchemeWireguard, "peer", "")
+	}
+	that.setAddresses(addresses)
+	return nil
+}
+
+// setAddresses keeps the first v4 and the first v6 interface address.
+func (that *ParserWirguard) setAddresses(addresses []string) {
+	for _, addr := range addresses {
+		ip, _, _ := strings.Cut(addr, "/")
+		if strings.Contains(ip, ":") {
+			if that.AddrV6 == "" {
+				that.AddrV6 = addr
+			}
+		} else if that.AddrV4 == "" {
+			that.AddrV4 = addr
+		}
+	}
+}
+
+// setPeers resolves every peer's endpoint and mirrors the first peer to the top level fields.
+func (that *ParserWirguard) setPeers() error {
+	if len(that.Peers) == 0 {
+		// json blob, the peer is described by the top level fields.
+		endpoint := that.Endpoint
+		if endpoint == "" && that.Address != "" {
+			endpoint = net.JoinHostPort(that.Address, strconv.Itoa(that.Port))
+		}
+		that.Peers = []*WireguardPeer{{
+			PublicKey:  that.PublicKey,
+			AllowedIPs: that.AllowedIPs,
+			Endpoint:   endpoint,
+		}}
+	}
+	for _, peer := range that.Peers {
+		host, rawPort, err := net.SplitHostPort(peer.Endpoint)
+		if err != nil {
+			return NewParseError(ErrMissingHost, SchemeWireguard, "endpoint", peer.Endpoint, err)
+		}
+		if peer.Port, err = checkHostPort(SchemeWireguard, host, rawPort); err != nil {
+			return err
+		}
+		peer.Address = host
+		if peer.PublicKey == "" {
+			return NewParseError(ErrMissingCredential, SchemeWireguard, "public_key", "")
+		}
+	}
+	first := that.Peers[0]
+	that.PublicKey = first.PublicKey
+	that.Endpoint = first.Endpoint
+	that.AllowedIPs = first.AllowedIPs
+	that.Address = first.Address
+	that.Port = first.Port
+	return nil
+}
+
+// LocalAddresses returns the interface addresses in cidr notation.
+func (that *ParserWirguard) LocalAddresses() (result []string) {
+	if that.AddrV4 != "" {
+		result = append(result, withPrefixLen(that.AddrV4, "32"))
+	}
+	if that.AddrV6 != "" {
+		result = append(result, withPrefixLen(that.AddrV6, "128"))
+	}
+	return
+}
+
+func withPrefixLen(addr, bits string) string {
+	if strings.Contains(addr, "/") {
+		return addr
+	}
+	return addr + "/" + bits
+}
+
+func splitList(s string) (result []string) {
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return
 }
 
-func (that *ParserWirguard) Parse(rawUri string) {
-	if strings.Contains(rawUri, SchemeWireguard) {
-		rawUri = strings.ReplaceAll(rawUri, SchemeWireguard, "")
+// parseReserved accepts "1,2,3", "[1,2,3]" or the base64 client id used by warp.
+func parseReserved(s string) ([]int, error) {
+	s = strings.Trim(strings.TrimSpace(s), "[]")
+	if !strings.Contains(s, ",") {
+		b, err := base64.StdEncoding.DecodeString(s)
+		if err != nil || len(b) != 3 {
+			return nil, NewParseError(ErrInvalidFormat, SchemeWireguard, "reserved", s, err)
+		}
+		return []int{int(b[0]), int(b[1]), int(b[2])}, nil
 	}
-	if err := json.Unmarshal([]byte(rawUri), that); err != nil {
-		gtui.PrintError(err)
+	result := []int{}
+	for _, item := range splitList(s) {
+		n, err := strconv.Atoi(item)
+		if err != nil || n < 0 || n > 255 {
+			return nil, NewParseError(ErrInvalidFormat, SchemeWireguard, "reserved", s, err)
+		}
+		result = append(result, n)
 	}
+	return result, nil
 }
 
 func (that *ParserWirguard) GetAddr() string {
@@ -57,11 +358,12 @@ func (that *ParserWirguard) GetPort() int {
 }
 
 func (that *ParserWirguard) Show() {
-	fmt.Printf("addr: %s, port: %d, privateKey: %s, publicKey: %s\n",
+	fmt.Printf("addr: %s, port: %d, privateKey: %s, publicKey: %s, peers: %d\n",
 		that.Address,
 		that.Port,
 		that.PrivateKey,
 		that.PublicKey,
+		len(that.Peers),
 	)
 }
 
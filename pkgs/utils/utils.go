@@ -21,11 +21,20 @@ func SetJsonObjectByString(key, value string, gJSON *gjson.Json) (newGJSON *gjso
 	return gjson.New(result)
 }
 
+// Scheme aliases that share a parser with their canonical scheme.
+var schemeAliases = map[string]string{
+	"hy2://": "hysteria2://",
+	"wg://":  "wireguard://",
+}
+
 func ParseScheme(rawUri string) (scheme string) {
 	sp := "://"
-	sList := strings.Split(rawUri, sp)
-	if len(sList) == 2 {
-		scheme = sList[0] + sp
+	// remarks may contain urls too, so only the first separator counts.
+	if i := strings.Index(rawUri, sp); i > 0 {
+		scheme = rawUri[:i] + sp
+	}
+	if s, ok := schemeAliases[scheme]; ok {
+		scheme = s
 	}
 	return
 }